@@ -1,10 +1,19 @@
 package github
 
-import log "github.com/mtrense/soil/logging"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/mtrense/soil/logging"
+	"golang.org/x/crypto/ssh"
+)
 
 type FullAudit struct {
-	Members      []*Member     `json:"members,omitempty"`
-	Repositories []*Repository `json:"repositories,omitempty"`
+	Members      []*Member      `json:"members,omitempty"`
+	Identities   *IdentityAudit `json:"identities,omitempty"`
+	Repositories []*Repository  `json:"repositories,omitempty"`
 }
 
 type Permission struct {
@@ -14,45 +23,95 @@ type Permission struct {
 	Role            string `json:"role,omitempty"`
 }
 
-func (s *GithubClient) FullAudit(org string) (FullAudit, error) {
+func (s *GithubClient) FullAudit(org string, filters ...RepositoryFilter) (FullAudit, error) {
 	var audit FullAudit
 	var err error
 	if audit.Members, err = s.GetMembers(org); err != nil {
 		return audit, err
 	}
+	if audit.Identities, err = s.identityAudit(org, audit.Members); err != nil {
+		return audit, err
+	}
 	if audit.Repositories, err = s.GetOrganizationRepositories(org); err != nil {
 		return audit, err
 	}
+	audit.Repositories = FilterRepositories(audit.Repositories, filters...)
 	if err := s.LoadRepositoryCollaborators(audit.Repositories...); err != nil {
 		return audit, err
 	}
 	return audit, nil
 }
 
+type IdentityAudit struct {
+	MembersWithoutIdentity []string            `json:"members_without_identity,omitempty"`
+	UnlinkedIdentities     []*ExternalIdentity `json:"unlinked_identities,omitempty"`
+}
+
+// identityAudit links the SAML identities of the organization to the given members. It returns nil if the organization
+// has no SAML identity provider configured.
+func (s *GithubClient) identityAudit(org string, members []*Member) (*IdentityAudit, error) {
+	identities, enabled, err := s.GetExternalIdentities(org)
+	if err != nil || !enabled {
+		return nil, err
+	}
+	audit := &IdentityAudit{
+		UnlinkedIdentities: linkExternalIdentities(identities, members),
+	}
+	for _, m := range members {
+		if !m.Pending && m.ExternalIdentity == nil {
+			audit.MembersWithoutIdentity = append(audit.MembersWithoutIdentity, m.Login)
+		}
+	}
+	return audit, nil
+}
+
 type TeamMembershipAudit struct {
 	Login       string           `json:"login,omitempty"`
 	Memberships []TeamMembership `json:"memberships,omitempty"`
 }
 
+const (
+	MembershipDirect    = "direct"
+	MembershipInherited = "inherited"
+)
+
 type TeamMembership struct {
 	TeamName string `json:"team_name,omitempty"`
+	TeamSlug string `json:"team_slug,omitempty"`
 	Role     string `json:"role,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Via      string `json:"via,omitempty"`
 }
 
+// TeamMembershipAudit lists for every login all teams it effectively belongs to. Members of child teams are reported
+// as inherited members of all parent teams, which always grants them the member role there.
 func (s *GithubClient) TeamMembershipAudit(org string) ([]TeamMembershipAudit, error) {
 	memberships := make(map[string][]TeamMembership)
 	var audit []TeamMembershipAudit
 	if teams, err := s.GetTeams(org); err == nil {
-		if err := s.LoadTeamMembers(org, teams...); err != nil {
+		if err := s.LoadImmediateTeamMembers(org, teams...); err != nil {
 			return audit, err
 		}
-		for _, team := range teams {
-			for _, m := range team.Members {
-				memberships[m.Login] = append(memberships[m.Login], TeamMembership{
-					TeamName: team.Name,
-					Role:     m.Role,
-				})
-			}
+		for _, root := range BuildTeamTree(teams) {
+			root.Walk(func(team *TeamNode, depth int) {
+				for _, m := range team.Members {
+					memberships[m.Login] = append(memberships[m.Login], TeamMembership{
+						TeamName: team.Name,
+						TeamSlug: team.Slug,
+						Role:     m.Role,
+						Source:   MembershipDirect,
+					})
+				}
+				for _, m := range team.InheritedMembers {
+					memberships[m.Login] = append(memberships[m.Login], TeamMembership{
+						TeamName: team.Name,
+						TeamSlug: team.Slug,
+						Role:     m.Role,
+						Source:   MembershipInherited,
+						Via:      m.Via,
+					})
+				}
+			})
 		}
 		for login, ms := range memberships {
 			audit = append(audit, TeamMembershipAudit{
@@ -71,12 +130,15 @@ type TeamPermissionAudit struct {
 	Permissions []Permission `json:"permissions,omitempty"`
 }
 
-func (s *GithubClient) TeamPermissionAudit(org string) ([]TeamPermissionAudit, error) {
+func (s *GithubClient) TeamPermissionAudit(org string, filters ...RepositoryFilter) ([]TeamPermissionAudit, error) {
 	var audit []TeamPermissionAudit
 	if teams, err := s.GetTeams(org); err == nil {
 		if err := s.LoadTeamRepositories(org, teams...); err != nil {
 			return audit, err
 		}
+		if err := s.restrictTeamRepositories(org, teams, filters...); err != nil {
+			return audit, err
+		}
 		for _, team := range teams {
 			a := TeamPermissionAudit{
 				Name: team.Name,
@@ -101,11 +163,12 @@ type MemberPermissionAudit struct {
 	Permissions []Permission `json:"permissions,omitempty"`
 }
 
-func (s *GithubClient) MemberPermissionAudit(org string) ([]MemberPermissionAudit, error) {
+func (s *GithubClient) MemberPermissionAudit(org string, filters ...RepositoryFilter) ([]MemberPermissionAudit, error) {
 	memberships := make(map[string][]Permission)
 	var audit []MemberPermissionAudit
 	log.L().Info().Msg("Fetching Repositories")
 	if repositories, err := s.GetOrganizationRepositories(org); err == nil {
+		repositories = FilterRepositories(repositories, filters...)
 		log.L().Info().Msg("Fetching Repository Collaborators")
 		if err := s.LoadRepositoryCollaborators(repositories...); err != nil {
 			return audit, err
@@ -151,9 +214,10 @@ type ActionAudit struct {
 	UsageWindows         int64   `json:"usage_windows,omitempty"`
 }
 
-func (s *GithubClient) ActionsAudit(org string) (ActionsAudit, error) {
+func (s *GithubClient) ActionsAudit(org string, filters ...RepositoryFilter) (ActionsAudit, error) {
 	var audit ActionsAudit
 	if repositories, err := s.GetOrganizationRepositories(org); err == nil {
+		repositories = FilterRepositories(repositories, filters...)
 		if err := s.LoadRepositoryWorkflows(repositories...); err != nil {
 			return audit, err
 		}
@@ -182,3 +246,384 @@ func (s *GithubClient) ActionsAudit(org string) (ActionsAudit, error) {
 		return audit, err
 	}
 }
+
+type TwoFactorAudit struct {
+	RequiresTwoFactor bool                  `json:"requires_two_factor"`
+	NonCompliant      []*TwoFactorViolation `json:"non_compliant,omitempty"`
+}
+
+type TwoFactorViolation struct {
+	Login      string `json:"login,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Role       string `json:"role,omitempty"`
+	AdminCount int    `json:"admin_count"`
+	WriteCount int    `json:"write_count"`
+}
+
+func (s *GithubClient) TwoFactorAudit(org string, filters ...RepositoryFilter) (TwoFactorAudit, error) {
+	var audit TwoFactorAudit
+	if organization, err := s.GetOrganization(org); err == nil {
+		audit.RequiresTwoFactor = organization.TwoFactorRequirementEnabled
+	} else {
+		return audit, err
+	}
+	var accounts []*Member
+	if members, err := s.GetMembers(org); err == nil {
+		accounts = append(accounts, members...)
+	} else {
+		return audit, err
+	}
+	if collaborators, err := s.GetOutsideCollaborators(org); err == nil {
+		accounts = append(accounts, collaborators...)
+	} else {
+		return audit, err
+	}
+	violations := make(map[string]*TwoFactorViolation)
+	for _, account := range accounts {
+		if account.Pending || account.HasTwoFactorEnabled {
+			continue
+		}
+		v := &TwoFactorViolation{
+			Login: account.Login,
+			Name:  account.Name,
+			Role:  account.Role,
+		}
+		violations[account.Login] = v
+		audit.NonCompliant = append(audit.NonCompliant, v)
+	}
+	if len(violations) == 0 {
+		return audit, nil
+	}
+	log.L().Info().Msg("Fetching Repositories")
+	if repositories, err := s.GetOrganizationRepositories(org); err == nil {
+		repositories = FilterRepositories(repositories, filters...)
+		log.L().Info().Msg("Fetching Repository Collaborators")
+		if err := s.LoadRepositoryCollaborators(repositories...); err != nil {
+			return audit, err
+		}
+		for _, repository := range repositories {
+			for _, collaborator := range repository.Collaborators {
+				if v, ok := violations[collaborator.Login]; ok {
+					switch collaborator.EffectivePermission {
+					case "ADMIN":
+						v.AdminCount++
+					case "MAINTAIN", "WRITE":
+						v.WriteCount++
+					}
+				}
+			}
+		}
+		return audit, nil
+	} else {
+		return audit, err
+	}
+}
+
+func (s *GithubClient) TwoFactorRemovalPlan(org string) (*Plan, error) {
+	audit, err := s.TwoFactorAudit(org)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	for _, v := range audit.NonCompliant {
+		login := v.Login
+		reason := fmt.Sprintf("two-factor authentication disabled (admin on %d, write on %d repositories)", v.AdminCount, v.WriteCount)
+		if v.Role == RoleOutsideCollaborator {
+			plan.Add("remove-outside-collaborator", login, "", reason, func() error {
+				return s.RemoveOutsideCollaborator(org, login)
+			})
+		} else {
+			plan.Add("remove-member", login, v.Role, reason, func() error {
+				return s.RemoveMember(org, login)
+			})
+		}
+	}
+	return plan, nil
+}
+
+type InteractionLimitAudit struct {
+	Organization *InteractionLimit            `json:"organization,omitempty"`
+	Repositories []RepositoryInteractionLimit `json:"repositories,omitempty"`
+}
+
+type RepositoryInteractionLimit struct {
+	Repository string            `json:"repository,omitempty"`
+	Limit      *InteractionLimit `json:"limit,omitempty"`
+}
+
+func (s *GithubClient) InteractionLimitAudit(org string, filters ...RepositoryFilter) (InteractionLimitAudit, error) {
+	var audit InteractionLimitAudit
+	var err error
+	if audit.Organization, err = s.GetInteractionLimit(org); err != nil {
+		return audit, err
+	}
+	if repositories, err := s.GetOrganizationRepositories(org); err == nil {
+		repositories = FilterRepositories(repositories, filters...)
+		for _, repository := range repositories {
+			// Interaction limits only apply to public repositories
+			if repository.Private || repository.Archived {
+				continue
+			}
+			if err := s.LoadRepositoryInteractionLimits(repository); err != nil {
+				return audit, err
+			}
+			if repository.InteractionLimit != nil && repository.InteractionLimit.Origin == "repository" {
+				audit.Repositories = append(audit.Repositories, RepositoryInteractionLimit{
+					Repository: repository.Name,
+					Limit:      repository.InteractionLimit,
+				})
+			}
+		}
+		return audit, nil
+	} else {
+		return audit, err
+	}
+}
+
+type TeamHygieneAudit struct {
+	EmptyTeams                []string   `json:"empty_teams,omitempty"`
+	TeamsWithoutRepositories  []string   `json:"teams_without_repositories,omitempty"`
+	TeamsWithoutMaintainers   []string   `json:"teams_without_maintainers,omitempty"`
+	TeamsOnlyOnArchivedRepos  []string   `json:"teams_only_on_archived_repositories,omitempty"`
+	TeamsWithIdenticalMembers [][]string `json:"teams_with_identical_members,omitempty"`
+	// TeamsOnlyWithDepartedMembers is only reported for organizations with a SAML identity provider.
+	TeamsOnlyWithDepartedMembers []string `json:"teams_only_with_departed_members,omitempty"`
+}
+
+// TeamHygieneAudit reports teams that are candidates for a cleanup. Teams are referenced by their slug. A team is
+// considered empty if it has neither direct members nor child teams. If the organization uses a SAML identity
+// provider, members without a linked identity are considered to have departed.
+func (s *GithubClient) TeamHygieneAudit(org string) (TeamHygieneAudit, error) {
+	var audit TeamHygieneAudit
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return audit, err
+	}
+	if err := s.LoadImmediateTeamMembers(org, teams...); err != nil {
+		return audit, err
+	}
+	if err := s.LoadTeamRepositories(org, teams...); err != nil {
+		return audit, err
+	}
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return audit, err
+	}
+	archived := make(map[string]bool)
+	for _, r := range repositories {
+		archived[r.Owner+"/"+r.Name] = r.Archived
+	}
+	identities, saml, err := s.GetExternalIdentities(org)
+	if err != nil {
+		return audit, err
+	}
+	linked := make(map[string]bool)
+	for _, identity := range identities {
+		if identity.Login != "" {
+			linked[strings.ToLower(identity.Login)] = true
+		}
+	}
+	byMembers := make(map[string][]string)
+	var memberSets []string
+	for _, team := range teams {
+		if len(team.Members) == 0 && team.ChildCount == 0 {
+			audit.EmptyTeams = append(audit.EmptyTeams, team.Slug)
+		}
+		if len(team.Repositories) == 0 {
+			audit.TeamsWithoutRepositories = append(audit.TeamsWithoutRepositories, team.Slug)
+		} else {
+			onlyArchived := true
+			for _, r := range team.Repositories {
+				onlyArchived = onlyArchived && archived[r.Owner+"/"+r.Name]
+			}
+			if onlyArchived {
+				audit.TeamsOnlyOnArchivedRepos = append(audit.TeamsOnlyOnArchivedRepos, team.Slug)
+			}
+		}
+		if len(team.Members) == 0 {
+			continue
+		}
+		var logins []string
+		hasMaintainer, onlyDeparted := false, true
+		for _, m := range team.Members {
+			logins = append(logins, m.Login)
+			hasMaintainer = hasMaintainer || m.Role == TeamRoleMaintainer
+			onlyDeparted = onlyDeparted && !linked[strings.ToLower(m.Login)]
+		}
+		if !hasMaintainer {
+			audit.TeamsWithoutMaintainers = append(audit.TeamsWithoutMaintainers, team.Slug)
+		}
+		if saml && onlyDeparted {
+			audit.TeamsOnlyWithDepartedMembers = append(audit.TeamsOnlyWithDepartedMembers, team.Slug)
+		}
+		sort.Strings(logins)
+		key := strings.Join(logins, ",")
+		if _, ok := byMembers[key]; !ok {
+			memberSets = append(memberSets, key)
+		}
+		byMembers[key] = append(byMembers[key], team.Slug)
+	}
+	for _, key := range memberSets {
+		if len(byMembers[key]) > 1 {
+			audit.TeamsWithIdenticalMembers = append(audit.TeamsWithIdenticalMembers, byMembers[key])
+		}
+	}
+	return audit, nil
+}
+
+// TeamCleanupPlan plans the deletion of all empty teams. The other findings of the hygiene audit need a human decision
+// and are not part of the plan.
+func (s *GithubClient) TeamCleanupPlan(org string) (*Plan, error) {
+	audit, err := s.TeamHygieneAudit(org)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	reasons := make(map[string]string)
+	var slugs []string
+	for _, slug := range audit.EmptyTeams {
+		reasons[slug] = "team has no members and no child teams"
+		slugs = append(slugs, slug)
+	}
+	for _, slug := range audit.TeamsOnlyOnArchivedRepos {
+		if reason, ok := reasons[slug]; ok {
+			reasons[slug] = reason + " and only access to archived repositories"
+		}
+	}
+	for _, slug := range slugs {
+		slug := slug
+		plan.Add("delete-team", slug, "", reasons[slug], func() error {
+			return s.DeleteTeam(org, slug)
+		})
+	}
+	return plan, nil
+}
+
+// DeployKeyPolicy configures the deploy key audit. Keys older than MaxAge days and RSA keys with less than
+// MinRSABits are flagged, DSA keys are always considered weak.
+type DeployKeyPolicy struct {
+	MaxAge     int `json:"max_age"`
+	MinRSABits int `json:"min_rsa_bits"`
+}
+
+type DeployKeyIssue struct {
+	Repository  string `json:"repository,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+func (s *GithubClient) DeployKeyAudit(org string, policy DeployKeyPolicy, filters ...RepositoryFilter) ([]DeployKeyIssue, error) {
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return nil, err
+	}
+	var active []*Repository
+	for _, repository := range FilterRepositories(repositories, filters...) {
+		if !repository.Archived {
+			active = append(active, repository)
+		}
+	}
+	if err := s.LoadRepositoryDeployKeys(active...); err != nil {
+		return nil, err
+	}
+	usages := make(map[string][]string)
+	for _, repository := range active {
+		for _, key := range repository.DeployKeys {
+			if key.Fingerprint != nil {
+				usages[*key.Fingerprint] = append(usages[*key.Fingerprint], repository.Name)
+			}
+		}
+	}
+	maxAge := time.Duration(policy.MaxAge) * 24 * time.Hour
+	var issues []DeployKeyIssue
+	for _, repository := range active {
+		for _, key := range repository.DeployKeys {
+			issue := func(problem string) {
+				issues = append(issues, DeployKeyIssue{
+					Repository:  repository.Name,
+					Title:       unboxString(key.Title),
+					Fingerprint: unboxString(key.Fingerprint),
+					Problem:     problem,
+				})
+			}
+			if !unboxBool(key.ReadOnly) {
+				issue("key has write access")
+			}
+			if policy.MaxAge > 0 && key.CreatedAt != nil && time.Since(*key.CreatedAt) > maxAge {
+				issue(fmt.Sprintf("key is older than %d days", policy.MaxAge))
+			}
+			switch keyType := unboxString(key.Type); {
+			case keyType == "":
+				issue("key can not be parsed")
+			case keyType == ssh.KeyAlgoDSA:
+				issue("key uses the weak algorithm " + keyType)
+			case keyType == ssh.KeyAlgoRSA && key.Bits != nil && *key.Bits < policy.MinRSABits:
+				issue(fmt.Sprintf("key has only %d bits", *key.Bits))
+			}
+			if key.Fingerprint != nil && len(usages[*key.Fingerprint]) > 1 {
+				var others []string
+				for _, name := range usages[*key.Fingerprint] {
+					if name != repository.Name {
+						others = append(others, name)
+					}
+				}
+				issue("key is also used by " + strings.Join(others, ", "))
+			}
+		}
+	}
+	return issues, nil
+}
+
+type SecretIssue struct {
+	// Repository is empty for secrets of the organization.
+	Repository  string `json:"repository,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+// SecretAudit reports Actions secrets of the organization, its non-archived repositories and their environments that
+// have not been updated within maxAge days, as well as organization secrets visible to all repositories.
+func (s *GithubClient) SecretAudit(org string, maxAge int, filters ...RepositoryFilter) ([]SecretIssue, error) {
+	var issues []SecretIssue
+	stale := func(secret ActionsSecret) bool {
+		return maxAge > 0 && time.Since(secret.UpdatedAt) > time.Duration(maxAge)*24*time.Hour
+	}
+	staleProblem := fmt.Sprintf("not updated within %d days", maxAge)
+	if secrets, _, err := s.GetOrganizationActionsSecrets(org); err == nil {
+		for _, secret := range secrets {
+			if stale(secret) {
+				issues = append(issues, SecretIssue{Secret: secret.Name, Problem: staleProblem})
+			}
+			if secret.Visibility == VisibilityAll {
+				issues = append(issues, SecretIssue{Secret: secret.Name, Problem: "visible to all repositories"})
+			}
+		}
+	} else {
+		return issues, err
+	}
+	if repositories, err := s.GetOrganizationRepositories(org); err == nil {
+		for _, repository := range FilterRepositories(repositories, filters...) {
+			if repository.Archived {
+				continue
+			}
+			if err := s.LoadRepositoryActionsSecrets(repository); err != nil {
+				return issues, err
+			}
+			for _, secret := range repository.Secrets {
+				if stale(secret) {
+					issues = append(issues, SecretIssue{
+						Repository:  repository.Name,
+						Environment: secret.Environment,
+						Secret:      secret.Name,
+						Problem:     staleProblem,
+					})
+				}
+			}
+		}
+		return issues, nil
+	} else {
+		return issues, err
+	}
+}
@@ -0,0 +1,149 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+// BranchRename reports the rename of a branch along with the references to the old name that remain afterwards.
+type BranchRename struct {
+	Repository string `json:"repository,omitempty"`
+	From       string `json:"from,omitempty"`
+	To         string `json:"to,omitempty"`
+	// ProtectionRules are the patterns of branch protection rules still referencing the old name.
+	ProtectionRules []string `json:"protection_rules,omitempty"`
+	// Workflows are the references to the old name in branch filters of workflow triggers.
+	Workflows []WorkflowBranchReference `json:"workflows,omitempty"`
+}
+
+type WorkflowBranchReference struct {
+	Path   string `json:"path,omitempty"`
+	Filter string `json:"filter,omitempty"`
+}
+
+// RenameBranch renames the branch using the Github rename API, which also retargets open pull requests and branch
+// protection rules and updates the default branch if it is renamed.
+func (s *GithubClient) RenameBranch(owner string, repository string, from string, to string) error {
+	req, err := s.v3Client.NewRequest("POST", fmt.Sprintf("repos/%v/%v/branches/%v/rename", owner, repository, url.PathEscape(from)), map[string]string{
+		"new_name": to,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.v3Client.Do(context.Background(), req, nil)
+	return err
+}
+
+// RenameDefaultBranch renames the default branch of the repository and verifies that no branch protection rule or
+// workflow trigger still references the old name. If only the verification fails, the rename is returned along with
+// the error.
+func (s *GithubClient) RenameDefaultBranch(repository *Repository, name string) (*BranchRename, error) {
+	from := repository.DefaultBranch
+	if err := s.RenameBranch(repository.Owner, repository.Name, from, name); err != nil {
+		return nil, err
+	}
+	repository.DefaultBranch = name
+	rename, err := s.CheckBranchReferences(repository, from)
+	if err != nil {
+		return &BranchRename{Repository: repository.Name, From: from, To: name}, fmt.Errorf("checking references to %s in %s: %w", from, repository.Name, err)
+	}
+	rename.To = name
+	return rename, nil
+}
+
+// CheckBranchReferences looks up branch protection rules and workflow triggers on the default branch of the
+// repository that reference the given branch name.
+func (s *GithubClient) CheckBranchReferences(repository *Repository, branch string) (*BranchRename, error) {
+	result := &BranchRename{
+		Repository: repository.Name,
+		From:       branch,
+	}
+	repository.BranchProtectionRules = nil
+	if err := s.loadRepositoryBranchProtectionRules(repository); err != nil {
+		return nil, err
+	}
+	for _, rule := range repository.BranchProtectionRules {
+		if rule.Pattern == branch {
+			result.ProtectionRules = append(result.ProtectionRules, rule.Pattern)
+		}
+	}
+	ctx := context.Background()
+	options := &gh3.RepositoryContentGetOptions{Ref: repository.DefaultBranch}
+	_, files, resp, err := s.v3Client.Repositories.GetContents(ctx, repository.Owner, repository.Name, ".github/workflows", options)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return result, nil
+		}
+		return nil, err
+	}
+	for _, f := range files {
+		if f.GetType() != "file" || !(strings.HasSuffix(f.GetName(), ".yml") || strings.HasSuffix(f.GetName(), ".yaml")) {
+			continue
+		}
+		file, _, _, err := s.v3Client.Repositories.GetContents(ctx, repository.Owner, repository.Name, f.GetPath(), options)
+		if err != nil {
+			return nil, err
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, err
+		}
+		filters, err := WorkflowBranchFilters(content, branch)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.GetPath(), err)
+		}
+		for _, filter := range filters {
+			result.Workflows = append(result.Workflows, WorkflowBranchReference{Path: f.GetPath(), Filter: filter})
+		}
+	}
+	return result, nil
+}
+
+// WorkflowBranchFilters returns the branch filters of the triggers of the workflow (e.g. "push.branches") that
+// contain the given branch name.
+func WorkflowBranchFilters(workflow string, branch string) ([]string, error) {
+	var document map[interface{}]interface{}
+	if err := yaml.Unmarshal([]byte(workflow), &document); err != nil {
+		return nil, err
+	}
+	on, ok := document["on"]
+	if !ok {
+		// YAML 1.1 reads an unquoted on as boolean
+		on = document[true]
+	}
+	triggers, ok := on.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil
+	}
+	var filters []string
+	for event, config := range triggers {
+		settings, ok := config.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"branches", "branches-ignore"} {
+			var branches []interface{}
+			switch value := settings[key].(type) {
+			case []interface{}:
+				branches = value
+			case string:
+				branches = []interface{}{value}
+			}
+			for _, b := range branches {
+				if b == branch {
+					filters = append(filters, fmt.Sprintf("%v.%s", event, key))
+				}
+			}
+		}
+	}
+	sort.Strings(filters)
+	return filters, nil
+}
@@ -0,0 +1,58 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("WorkflowBranchFilters", func() {
+	DescribeTable("finds branch filters referencing the branch",
+		func(workflow string, expected []string) {
+			filters, err := WorkflowBranchFilters(workflow, "master")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(filters).To(Equal(expected))
+		},
+		Entry("in push and pull request triggers", `
+on:
+  push:
+    branches: [master, develop]
+  pull_request:
+    branches:
+      - master
+`, []string{"pull_request.branches", "push.branches"}),
+		Entry("in ignored branches", `
+"on":
+  push:
+    branches-ignore: [master]
+`, []string{"push.branches-ignore"}),
+		Entry("in a single branch filter", `
+on:
+  push:
+    branches: master
+`, []string{"push.branches"}),
+		Entry("nothing for other branches", `
+on:
+  push:
+    branches: [main, master-old]
+`, nil),
+		Entry("nothing for triggers without filters", `
+on:
+  push:
+  workflow_dispatch: {}
+`, nil),
+		Entry("nothing for a list of events", `
+on: [push, pull_request]
+`, nil),
+		Entry("nothing without triggers", `
+name: build
+`, nil),
+	)
+
+	It("fails on invalid YAML", func() {
+		_, err := WorkflowBranchFilters("on: [push", "master")
+		Expect(err).To(HaveOccurred())
+	})
+})
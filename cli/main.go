@@ -1,6 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/engage-wf/core"
 	github "github.com/engage-wf/plugin-github"
 	. "github.com/mtrense/soil/config"
@@ -25,22 +32,213 @@ var (
 				SubCommand("list",
 					Short("List Members of this Organization"),
 					Alias("l"),
+					Flag("identities", Bool(), Description("Include linked SAML identities in listing")),
+					Flag("keys", Bool(), Description("Include SSH and GPG keys in listing")),
 					Run(executeOrganizationMembersList),
 				),
+				SubCommand("enforce-two-factor",
+					Short("Plan (or execute) the removal of Members and Outside Collaborators without two-factor authentication"),
+					Alias("e2fa"),
+					Flag("execute", Bool(), Description("Execute the removal plan instead of only printing it")),
+					Run(executeOrganizationMembersEnforceTwoFactor),
+				),
+			),
+			SubCommand("blocks",
+				Short("Users blocked by this Organization"),
+				Alias("b"),
+				SubCommand("list",
+					Short("List Users blocked by this Organization"),
+					Alias("l"),
+					Run(executeOrganizationBlocksList),
+				),
+				SubCommand("add",
+					Short("Block a User from this Organization"),
+					Alias("a"),
+					Args(One()),
+					Run(executeOrganizationBlocksAdd),
+				),
+				SubCommand("remove",
+					Short("Unblock a User from this Organization"),
+					Alias("rm"),
+					Args(One()),
+					Run(executeOrganizationBlocksRemove),
+				),
+			),
+			SubCommand("interaction-limits",
+				Short("Interaction limits of this Organization and its Repositories"),
+				Alias("il"),
+				SubCommand("show",
+					Short("Show Interaction limits active on this Organization and its Repositories"),
+					Alias("sh"),
+					Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)")),
+					Run(executeOrganizationInteractionLimitsShow),
+				),
+				SubCommand("set",
+					Short("Limit interactions on this Organization or one of its Repositories"),
+					Alias("s"),
+					Flag("limit", Str(""), Description("Limit to set (existing_users, contributors_only or collaborators_only)"), Mandatory()),
+					Flag("repository", Str(""), Description("Repository to limit instead of the whole Organization")),
+					Run(executeOrganizationInteractionLimitsSet),
+				),
+				SubCommand("remove",
+					Short("Remove the interaction limit from this Organization or one of its Repositories"),
+					Alias("rm"),
+					Flag("repository", Str(""), Description("Repository to remove the limit from instead of the whole Organization")),
+					Run(executeOrganizationInteractionLimitsRemove),
+				),
+			),
+			SubCommand("secrets",
+				Short("Actions secrets and variables of this Organization"),
+				Alias("sec"),
+				SubCommand("list",
+					Short("List Actions secrets (without values) and variables of this Organization along with their visibility"),
+					Alias("l", "ls"),
+					Run(executeOrganizationSecretsList),
+				),
+			),
+			SubCommand("webhooks",
+				Short("Webhooks of this Organization and its Repositories"),
+				Alias("wh", "hooks"),
+				Flag("repository", Str(""), Description("Repository to operate on instead of the whole Organization"), Persistent()),
+				SubCommand("list",
+					Short("List Webhooks of this Organization and its Repositories, or of a single Repository"),
+					Alias("l", "ls"),
+					Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)")),
+					Run(executeOrganizationWebhooksList),
+				),
+				SubCommand("create",
+					Short("Create a Webhook"),
+					Alias("c"),
+					Flag("url", Str(""), Description("URL the payloads are delivered to"), Mandatory()),
+					Flag("events", Str("push"), Description("Comma separated events triggering the Webhook")),
+					Flag("content-type", Str("json"), Description("Content type of the payloads (json or form)")),
+					Flag("secret", Str(""), Description("Secret used to sign the payloads"), Env()),
+					Flag("insecure-ssl", Bool(), Description("Disable SSL verification when delivering payloads")),
+					Flag("inactive", Bool(), Description("Create the Webhook without activating it")),
+					Run(executeOrganizationWebhooksCreate),
+				),
+				SubCommand("update",
+					Short("Update a Webhook, only given settings are changed"),
+					Alias("u"),
+					Args(One()),
+					Flag("url", Str(""), Description("URL the payloads are delivered to")),
+					Flag("events", Str(""), Description("Comma separated events triggering the Webhook")),
+					Flag("content-type", Str(""), Description("Content type of the payloads (json or form)")),
+					Flag("secret", Str(""), Description("New secret used to sign the payloads")),
+					Flag("insecure-ssl", Bool(), Description("Disable SSL verification when delivering payloads")),
+					Flag("active", Bool(), Description("Activate or deactivate the Webhook")),
+					Run(executeOrganizationWebhooksUpdate),
+				),
+				SubCommand("delete",
+					Short("Delete a Webhook"),
+					Alias("d", "rm"),
+					Args(One()),
+					Run(executeOrganizationWebhooksDelete),
+				),
+			),
+			SubCommand("settings",
+				Short("Settings of this Organization"),
+				Alias("s"),
+				SubCommand("show",
+					Short("Show the Settings of this Organization"),
+					Alias("sh"),
+					Run(executeOrganizationSettingsShow),
+				),
+				SubCommand("check",
+					Short("Compare the Settings of this Organization against a baseline read from stdin"),
+					Alias("c"),
+					Run(executeOrganizationSettingsCheck),
+				),
 			),
 			SubCommand("teams",
 				Short("Teams defined in this Organization"),
 				Alias("t"),
+				Flag("execute", Bool(), Description("Execute the plan instead of only printing it"), Persistent()),
 				SubCommand("list",
 					Short("List Teams defined in this Organization"),
 					Alias("l"),
 					Flag("members", Bool(), Description("Include Members in listing"), Persistent()),
+					Flag("tree", Bool(), Description("List Teams as hierarchy including inherited Members and Repositories")),
+					Flag("format", Str("json"), Description("Output format of the hierarchy (json or ascii)")),
 					Run(executeOrganizationTeamsList),
 				),
+				SubCommand("create",
+					Short("Create a new Team"),
+					Alias("c"),
+					Args(One()),
+					Flag("description", Str(""), Description("Description of the Team")),
+					Flag("privacy", Str(""), Description("Privacy of the Team (secret or closed)")),
+					Flag("parent", Str(""), Description("Slug of the parent Team")),
+					Run(executeOrganizationTeamsCreate),
+				),
+				SubCommand("update",
+					Short("Update the settings of a Team"),
+					Alias("u"),
+					Args(One()),
+					Flag("name", Str(""), Description("New name of the Team")),
+					Flag("description", Str(""), Description("Description of the Team")),
+					Flag("privacy", Str(""), Description("Privacy of the Team (secret or closed)")),
+					Flag("parent", Str(""), Description("Slug of the parent Team")),
+					Flag("remove-parent", Bool(), Description("Move the Team to the top of the hierarchy")),
+					Run(executeOrganizationTeamsUpdate),
+				),
+				SubCommand("sync",
+					Short("Synchronize Team membership with a YAML or CSV file"),
+					Alias("s"),
+					Flag("from", Str(""), Description("YAML or CSV file containing the desired Team membership"), Mandatory(), Filename("yaml", "yml", "csv")),
+					Flag("max-changes", Int(20), Description("Refuse plans with more changes than this (0 for no limit)")),
+					Run(executeOrganizationTeamsSync),
+				),
+				SubCommand("enforce-policy",
+					Short("Plan (or execute) the grants and revokes needed to comply with a Team permission policy"),
+					Alias("ep"),
+					Flag("policy", Str(""), Description("YAML or JSON file containing the policies"), Mandatory(), Filename("yaml", "yml", "json")),
+					Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)")),
+					Run(executeOrganizationTeamsEnforcePolicy),
+				),
+				SubCommand("cleanup",
+					Short("Plan (or execute) the deletion of empty Teams"),
+					Alias("cl"),
+					Run(executeOrganizationTeamsCleanup),
+				),
+				SubCommand("delete",
+					Short("Delete a Team"),
+					Alias("d"),
+					Args(One()),
+					Run(executeOrganizationTeamsDelete),
+				),
+				SubCommand("add-member",
+					Short("Add a Member to a Team or change the role of a Member"),
+					Alias("am"),
+					Args(Range(2, 2)),
+					Flag("role", Str("member"), Description("Role of the Member in the Team (member or maintainer)")),
+					Run(executeOrganizationTeamsAddMember),
+				),
+				SubCommand("remove-member",
+					Short("Remove a Member from a Team"),
+					Alias("rm"),
+					Args(Range(2, 2)),
+					Run(executeOrganizationTeamsRemoveMember),
+				),
+				SubCommand("grant",
+					Short("Grant a Team permission on a Repository"),
+					Alias("g"),
+					Args(Range(2, 2)),
+					Flag("permission", Str("pull"), Description("Permission to grant (pull, triage, push, maintain or admin)")),
+					Run(executeOrganizationTeamsGrant),
+				),
+				SubCommand("revoke",
+					Short("Revoke all permissions of a Team on a Repository"),
+					Alias("r"),
+					Args(Range(2, 2)),
+					Run(executeOrganizationTeamsRevoke),
+				),
 			),
 			SubCommand("repositories",
 				Short("Repositories in this Organization"),
 				Alias("r"),
+				Flag("topic", Str(""), Description("Only include Repositories with this topic"), Persistent()),
+				Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)"), Persistent()),
 				SubCommand("list",
 					Short("List Repositories in this Organization"),
 					Alias("l"),
@@ -48,15 +246,57 @@ var (
 					Flag("branch-protection", Bool(), Description("Fetch Branch Protection Configuration"), Persistent()),
 					Flag("languages", Bool(), Description("Fetch Repository Languages"), Persistent()),
 					Flag("workflows", Bool(), Description("Fetch defined Workflows"), Persistent()),
+					Flag("deploy-keys", Bool(), Description("Fetch Deploy Keys"), Persistent()),
+					Flag("secrets", Bool(), Description("Fetch Actions secrets and variables of Repositories and their environments"), Persistent()),
 					Flag("pattern", Str(""), Description("Pattern to match the Repository name against"), Persistent()),
 					Run(executeOrganizationRepositoriesList),
 				),
+				SubCommand("notify-stale",
+					Short("Plan (or execute) opening an issue announcing the archival in each abandoned Repository"),
+					Flag("dormant-after", Int(90), Description("Days without activity after which a Repository is dormant")),
+					Flag("abandoned-after", Int(365), Description("Days without activity after which a Repository is abandoned")),
+					Flag("execute", Bool(), Description("Execute the plan instead of only printing it")),
+					Run(executeOrganizationRepositoriesNotifyStale),
+				),
+				SubCommand("archive-stale",
+					Short("Plan (or execute) the archival of abandoned Repositories announced by notify-stale"),
+					Flag("dormant-after", Int(90), Description("Days without activity after which a Repository is dormant")),
+					Flag("abandoned-after", Int(365), Description("Days without activity after which a Repository is abandoned")),
+					Flag("grace-period", Int(30), Description("Days between the announcement and the archival of a Repository")),
+					Flag("execute", Bool(), Description("Execute the plan instead of only printing it")),
+					Run(executeOrganizationRepositoriesArchiveStale),
+				),
+				SubCommand("topics",
+					Short("Handle topics of Repositories in this Organization"),
+					Alias("t"),
+					Flag("repositories", Str(""), Description("Comma separated list of Repositories to change"), Persistent()),
+					Flag("execute", Bool(), Description("Execute the plan instead of only printing it"), Persistent()),
+					SubCommand("add",
+						Short("Add topics to Repositories"),
+						Alias("a"),
+						Args(Range(1, 20)),
+						Run(executeOrganizationRepositoriesTopicsAdd),
+					),
+					SubCommand("remove",
+						Short("Remove topics from Repositories"),
+						Alias("rm"),
+						Args(Range(1, 20)),
+						Run(executeOrganizationRepositoriesTopicsRemove),
+					),
+					SubCommand("replace",
+						Short("Replace all topics of Repositories"),
+						Alias("r"),
+						Args(Range(0, 20)),
+						Run(executeOrganizationRepositoriesTopicsReplace),
+					),
+				),
 			),
 			SubCommand("audit",
 				Short("Fetch user and permission information for the organization"),
 				SubCommand("full",
 					Short("Generate a full audit"),
 					Alias("f"),
+					repositorySelectionFlags(),
 					Run(executeOrganizationAuditFull),
 				),
 				SubCommand("team-membership",
@@ -67,34 +307,152 @@ var (
 				SubCommand("team-permission",
 					Short("Generate an audit on Team Permissions"),
 					Alias("tp"),
+					Flag("format", Str("json"), Description("Output format (json, or matrix, csv, markdown or html for a Team/Repository matrix)")),
+					repositorySelectionFlags(),
 					Run(executeOrganizationAuditTeamPermission),
 				),
 				SubCommand("member-permission",
 					Short("Generate an audit on Member Permissions"),
 					Alias("mp"),
+					repositorySelectionFlags(),
 					Run(executeOrganizationAuditMemberPermission),
 				),
 				SubCommand("actions",
 					Short("Generate an audit on configured Actions"),
 					Alias("a"),
+					repositorySelectionFlags(),
 					Run(executeOrganizationAuditActions),
 				),
+				SubCommand("team-policy",
+					Short("Generate an audit on violations of a Team permission policy"),
+					Alias("tpo"),
+					Flag("policy", Str(""), Description("YAML or JSON file containing the policies"), Mandatory(), Filename("yaml", "yml", "json")),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditTeamPolicy),
+				),
+				SubCommand("team-hygiene",
+					Short("Generate an audit on empty, unmaintained, duplicate and departed Teams"),
+					Alias("th"),
+					Run(executeOrganizationAuditTeamHygiene),
+				),
+				SubCommand("stale",
+					Short("Generate an audit classifying Repositories as active, dormant or abandoned"),
+					Alias("st"),
+					Flag("dormant-after", Int(90), Description("Days without activity after which a Repository is dormant")),
+					Flag("abandoned-after", Int(365), Description("Days without activity after which a Repository is abandoned")),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditStale),
+				),
+				SubCommand("secrets",
+					Short("Generate an audit on stale Actions secrets and Organization secrets visible to all Repositories"),
+					Alias("sec"),
+					Flag("max-age", Int(90), Description("Days after which a secret not updated is considered stale (0 to disable)")),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditSecrets),
+				),
+				SubCommand("deploy-keys",
+					Short("Generate an audit on write-enabled, old, weak and reused Deploy Keys"),
+					Alias("dk"),
+					Flag("max-age", Int(365), Description("Days after which a Deploy Key is considered old (0 to disable)")),
+					Flag("min-rsa-bits", Int(3072), Description("Minimum size of RSA Deploy Keys")),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditDeployKeys),
+				),
+				SubCommand("webhooks",
+					Short("Generate an audit on insecure Webhooks"),
+					Alias("wh"),
+					Flag("allow-hosts", Str(""), Description("Comma separated hosts Webhooks may deliver to, including their subdomains")),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditWebhooks),
+				),
+				SubCommand("two-factor",
+					Short("Generate an audit on two-factor authentication compliance"),
+					Alias("2fa"),
+					repositorySelectionFlags(),
+					Run(executeOrganizationAuditTwoFactor),
+				),
 			),
 		),
 		SubCommand("repositories",
 			Short("Handle Repositories"),
 			Alias("r", "repos", "repository"),
 			Flag("user", Str(""), Abbr("u"), Description("Name of the User/Organization to operate on"), Mandatory(), Env(), Persistent()),
+			Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)"), Persistent()),
 			SubCommand("list",
 				Short("List Repositories"),
 				Alias("l", "ls"),
-				//Run(executeRepositoriesList),
+				Flag("affiliation", Str("owner,collaborator,organization_member"), Description("Comma separated affiliations of the User to the Repositories")),
+				Flag("security", Bool(), Description("Fetch Security Configuration")),
+				Flag("branch-protection", Bool(), Description("Fetch Branch Protection Configuration")),
+				Flag("languages", Bool(), Description("Fetch Repository Languages")),
+				Flag("workflows", Bool(), Description("Fetch defined Workflows")),
+				Flag("deploy-keys", Bool(), Description("Fetch Deploy Keys")),
+				Flag("secrets", Bool(), Description("Fetch Actions secrets and variables of Repositories and their environments")),
+				Run(executeRepositoriesList),
 			),
 			SubCommand("create",
-				Short("Create a new Repository"),
+				Short("Create a new Repository, optionally granting Teams access and protecting its default branch"),
 				Alias("c"),
+				Flag("template", Str(""), Description("Create the Repository from the given template Repository (owner/name)")),
+				Flag("include-all-branches", Bool(), Description("Copy all branches of the template Repository")),
 				Run(executeRepositoriesCreate),
 			),
+			SubCommand("update",
+				Short("Update the settings of Repositories, reading a list of patches or a single patch applied to all selected Repositories from stdin"),
+				Alias("u"),
+				Flag("dry-run", Bool(), Description("Only print the changes without applying them")),
+				Run(executeRepositoriesUpdate),
+			),
+			SubCommand("archive",
+				Short("Archive Repositories given by name or selector"),
+				Flag("confirm", Bool(), Description("Confirm the archival, otherwise only the planned operations are printed")),
+				Flag("manifest", Str("manifest.json"), Description("File the performed operations are appended to")),
+				Run(executeRepositoriesArchive),
+			),
+			SubCommand("unarchive",
+				Short("Unarchive Repositories given by name or selector"),
+				Flag("confirm", Bool(), Description("Confirm the unarchival, otherwise only the planned operations are printed")),
+				Flag("manifest", Str("manifest.json"), Description("File the performed operations are appended to")),
+				Run(executeRepositoriesUnarchive),
+			),
+			SubCommand("rename",
+				Short("Rename a Repository"),
+				Args(Range(2, 2)),
+				Flag("confirm", Bool(), Description("Confirm the rename, otherwise only the planned operation is printed")),
+				Flag("manifest", Str("manifest.json"), Description("File the performed operations are appended to")),
+				Run(executeRepositoriesRename),
+			),
+			SubCommand("transfer",
+				Short("Transfer Repositories given by name or selector to another Organization"),
+				Flag("to", Str(""), Description("Organization to transfer the Repositories to"), Mandatory()),
+				Flag("team-mapping", Str(""), Description("Comma separated Team mappings (source=target), target Teams are given access to the transferred Repositories")),
+				Flag("confirm", Bool(), Description("Confirm the transfer, otherwise only the planned operations are printed")),
+				Flag("manifest", Str("manifest.json"), Description("File the performed operations are appended to")),
+				Run(executeRepositoriesTransfer),
+			),
+			SubCommand("delete",
+				Short("Delete Repositories given by name or selector"),
+				Flag("confirm", Bool(), Description("Confirm the deletion, otherwise only the planned operations are printed")),
+				Flag("manifest", Str("manifest.json"), Description("File the performed operations are appended to")),
+				Run(executeRepositoriesDelete),
+			),
+			SubCommand("rename-default-branch",
+				Short("Rename the default branch of Repositories given by name or selector and report remaining references to the old name"),
+				Alias("rdb"),
+				Flag("from", Str("master"), Description("Only rename default branches with this name")),
+				Flag("to", Str("main"), Description("New name of the default branch")),
+				Flag("confirm", Bool(), Description("Confirm the renames, otherwise only the planned operations are printed")),
+				Run(executeRepositoriesRenameDefaultBranch),
+			),
+			SubCommand("codeowners",
+				Short("Handle CODEOWNERS files"),
+				Alias("co"),
+				SubCommand("check",
+					Short("Validate CODEOWNERS files against Teams, Members and Repository contents"),
+					Alias("c"),
+					Run(executeRepositoriesCodeOwnersCheck),
+				),
+			),
 		),
 		SubCommand("user",
 			Short("Handle Users"),
@@ -132,17 +490,246 @@ func main() {
 
 func executeOrganizationMembersList(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if members, err := gh().GetMembers(org); err == nil {
+	identities, _ := cmd.Flags().GetBool("identities")
+	keys, _ := cmd.Flags().GetBool("keys")
+	client := gh()
+	if members, err := client.GetMembers(org); err == nil {
+		if identities {
+			if _, err := client.LoadMemberExternalIdentities(org, members...); err != nil {
+				panic(err)
+			}
+		}
+		if keys {
+			if err := client.LoadMemberKeys(members...); err != nil {
+				panic(err)
+			}
+		}
 		core.PrintJSON(members)
 	} else {
 		panic(err)
 	}
 }
 
+func executeOrganizationMembersEnforceTwoFactor(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if plan, err := gh().TwoFactorRemovalPlan(org); err == nil {
+		executePlan(cmd, plan)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationBlocksList(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if blocked, err := gh().GetBlockedUsers(org); err == nil {
+		core.PrintJSON(blocked)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationBlocksAdd(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if err := gh().BlockUser(org, args[0]); err != nil {
+		panic(err)
+	}
+}
+
+func executeOrganizationBlocksRemove(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if err := gh().UnblockUser(org, args[0]); err != nil {
+		panic(err)
+	}
+}
+
+func executeOrganizationInteractionLimitsShow(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if audit, err := gh().InteractionLimitAudit(org, repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationInteractionLimitsSet(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	limit, _ := cmd.Flags().GetString("limit")
+	repository, _ := cmd.Flags().GetString("repository")
+	var err error
+	if repository == "" {
+		err = gh().SetInteractionLimit(org, limit)
+	} else {
+		err = gh().SetRepositoryInteractionLimit(org, repository, limit)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func executeOrganizationInteractionLimitsRemove(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	repository, _ := cmd.Flags().GetString("repository")
+	var err error
+	if repository == "" {
+		err = gh().RemoveInteractionLimit(org)
+	} else {
+		err = gh().RemoveRepositoryInteractionLimit(org, repository)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func executeOrganizationSecretsList(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	secrets, variables, err := gh().GetOrganizationActionsSecrets(org)
+	if err != nil {
+		panic(err)
+	}
+	core.PrintJSON(struct {
+		Secrets   []github.ActionsSecret   `json:"secrets,omitempty"`
+		Variables []github.ActionsVariable `json:"variables,omitempty"`
+	}{secrets, variables})
+}
+
+func executeOrganizationWebhooksList(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	repository, _ := cmd.Flags().GetString("repository")
+	client := gh()
+	if repository != "" {
+		r := &github.Repository{Owner: org, Name: repository}
+		if err := client.LoadRepositoryWebhooks(r); err != nil {
+			panic(err)
+		}
+		core.PrintJSON(r.Webhooks)
+		return
+	}
+	webhooks, err := client.GetOrganizationWebhooks(org)
+	if err != nil {
+		panic(err)
+	}
+	repositories, err := client.GetOrganizationRepositories(org)
+	if err != nil {
+		panic(err)
+	}
+	for _, r := range github.FilterRepositories(repositories, repositoryFilters(cmd)...) {
+		if r.Archived {
+			continue
+		}
+		if err := client.LoadRepositoryWebhooks(r); err != nil {
+			panic(err)
+		}
+		webhooks = append(webhooks, r.Webhooks...)
+	}
+	core.PrintJSON(webhooks)
+}
+
+func executeOrganizationWebhooksCreate(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	repository, _ := cmd.Flags().GetString("repository")
+	settings := webhookSettings(cmd)
+	inactive, _ := cmd.Flags().GetBool("inactive")
+	active := !inactive
+	settings.Active = &active
+	if webhook, err := gh().CreateWebhook(org, repository, settings); err == nil {
+		core.PrintJSON(webhook)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationWebhooksUpdate(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	repository, _ := cmd.Flags().GetString("repository")
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	settings := webhookSettings(cmd)
+	if cmd.Flags().Changed("active") {
+		active, _ := cmd.Flags().GetBool("active")
+		settings.Active = &active
+	}
+	if webhook, err := gh().UpdateWebhook(org, repository, id, settings); err == nil {
+		core.PrintJSON(webhook)
+	} else {
+		panic(err)
+	}
+}
+
+// webhookSettings reads the settings shared by creating and updating Webhooks from the flags.
+func webhookSettings(cmd *cobra.Command) github.WebhookSettings {
+	var settings github.WebhookSettings
+	settings.URL, _ = cmd.Flags().GetString("url")
+	settings.ContentType, _ = cmd.Flags().GetString("content-type")
+	if cmd.Name() == "create" {
+		// the secret of a new webhook may also be given in the environment
+		settings.Secret = viper.GetString("secret")
+	} else {
+		settings.Secret, _ = cmd.Flags().GetString("secret")
+	}
+	if events, _ := cmd.Flags().GetString("events"); events != "" {
+		settings.Events = strings.Split(events, ",")
+	}
+	if cmd.Flags().Changed("insecure-ssl") || cmd.Name() == "create" {
+		insecure, _ := cmd.Flags().GetBool("insecure-ssl")
+		settings.InsecureSSL = &insecure
+	}
+	return settings
+}
+
+func executeOrganizationWebhooksDelete(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	repository, _ := cmd.Flags().GetString("repository")
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		panic(err)
+	}
+	if err := gh().DeleteWebhook(org, repository, id); err != nil {
+		panic(err)
+	}
+}
+
+func executeOrganizationSettingsShow(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if organization, err := gh().GetOrganization(org); err == nil {
+		core.PrintJSON(organization)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationSettingsCheck(cmd *cobra.Command, args []string) {
+	var baseline github.OrganizationBaseline
+	if err := core.ReadFromStdin(&baseline); err != nil {
+		panic(err)
+	}
+	org, _ := cmd.Flags().GetString("organization")
+	if organization, err := gh().GetOrganization(org); err == nil {
+		core.PrintJSON(baseline.Check(organization))
+	} else {
+		panic(err)
+	}
+}
+
 func executeOrganizationTeamsList(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
 	members, _ := cmd.Flags().GetBool("members")
+	tree, _ := cmd.Flags().GetBool("tree")
 	client := gh()
+	if tree {
+		format, _ := cmd.Flags().GetString("format")
+		if roots, err := client.GetTeamTree(org); err == nil {
+			if format == "ascii" {
+				fmt.Print(github.FormatTeamTree(roots))
+			} else {
+				core.PrintJSON(roots)
+			}
+		} else {
+			panic(err)
+		}
+		return
+	}
 	if teams, err := client.GetTeams(org); err == nil {
 		if members {
 			if err := client.LoadTeamMembers(org, teams...); err != nil {
@@ -155,43 +742,274 @@ func executeOrganizationTeamsList(cmd *cobra.Command, args []string) {
 	}
 }
 
+func executeOrganizationTeamsCreate(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	settings := github.TeamSettings{Name: args[0]}
+	settings.Description, _ = cmd.Flags().GetString("description")
+	settings.Privacy, _ = cmd.Flags().GetString("privacy")
+	settings.ParentSlug, _ = cmd.Flags().GetString("parent")
+	plan := &github.Plan{}
+	plan.Add("create-team", settings.Name, settings.ParentSlug, "", func() error {
+		team, err := gh().CreateTeam(org, settings)
+		if err == nil {
+			core.PrintJSON(team)
+		}
+		return err
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsUpdate(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	var settings github.TeamSettings
+	settings.Name, _ = cmd.Flags().GetString("name")
+	settings.Description, _ = cmd.Flags().GetString("description")
+	settings.Privacy, _ = cmd.Flags().GetString("privacy")
+	settings.ParentSlug, _ = cmd.Flags().GetString("parent")
+	settings.RemoveParent, _ = cmd.Flags().GetBool("remove-parent")
+	plan := &github.Plan{}
+	plan.Add("update-team", args[0], "", "", func() error {
+		team, err := gh().UpdateTeam(org, args[0], settings)
+		if err == nil {
+			core.PrintJSON(team)
+		}
+		return err
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsSync(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	from, _ := cmd.Flags().GetString("from")
+	maxChanges, _ := cmd.Flags().GetInt("max-changes")
+	f, err := os.Open(from)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	var desired github.DesiredTeams
+	if strings.EqualFold(filepath.Ext(from), ".csv") {
+		desired, err = github.ReadDesiredTeamsCSV(f)
+	} else {
+		desired, err = github.ReadDesiredTeamsYAML(f)
+	}
+	if err != nil {
+		panic(err)
+	}
+	plan, err := gh().TeamSyncPlan(org, desired, maxChanges)
+	if err != nil {
+		if plan != nil {
+			core.PrintJSON(plan)
+		}
+		panic(err)
+	}
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsEnforcePolicy(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if plan, err := gh().TeamPolicyPlan(org, readTeamPolicies(cmd), repositoryFilters(cmd)...); err == nil {
+		executePlan(cmd, plan)
+	} else {
+		panic(err)
+	}
+}
+
+func readTeamPolicies(cmd *cobra.Command) github.TeamPolicies {
+	filename, _ := cmd.Flags().GetString("policy")
+	f, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	policies, err := github.ReadTeamPolicies(f)
+	if err != nil {
+		panic(err)
+	}
+	return policies
+}
+
+func executeOrganizationTeamsCleanup(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if plan, err := gh().TeamCleanupPlan(org); err == nil {
+		executePlan(cmd, plan)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationTeamsDelete(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	plan := &github.Plan{}
+	plan.Add("delete-team", args[0], "", "", func() error {
+		return gh().DeleteTeam(org, args[0])
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsAddMember(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	role, _ := cmd.Flags().GetString("role")
+	plan := &github.Plan{}
+	plan.Add("add-team-member", args[0], args[1]+" as "+role, "", func() error {
+		return gh().AddTeamMember(org, args[0], args[1], role)
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsRemoveMember(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	plan := &github.Plan{}
+	plan.Add("remove-team-member", args[0], args[1], "", func() error {
+		return gh().RemoveTeamMember(org, args[0], args[1])
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsGrant(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	permission, _ := cmd.Flags().GetString("permission")
+	plan := &github.Plan{}
+	plan.Add("grant-team-repository", args[0], args[1]+" with "+permission, "", func() error {
+		return gh().GrantTeamRepository(org, args[0], org, args[1], permission)
+	})
+	executePlan(cmd, plan)
+}
+
+func executeOrganizationTeamsRevoke(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	plan := &github.Plan{}
+	plan.Add("revoke-team-repository", args[0], args[1], "", func() error {
+		return gh().RevokeTeamRepository(org, args[0], org, args[1])
+	})
+	executePlan(cmd, plan)
+}
+
 func executeOrganizationRepositoriesList(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
+	if repositories, err := gh().GetOrganizationRepositories(org); err == nil {
+		listRepositories(cmd, repositories)
+	} else {
+		panic(err)
+	}
+}
+
+func listRepositories(cmd *cobra.Command, repositories []*github.Repository) {
 	security, _ := cmd.Flags().GetBool("security")
 	branchProtection, _ := cmd.Flags().GetBool("branch-protection")
 	languages, _ := cmd.Flags().GetBool("languages")
 	workflows, _ := cmd.Flags().GetBool("workflows")
+	deployKeys, _ := cmd.Flags().GetBool("deploy-keys")
+	secrets, _ := cmd.Flags().GetBool("secrets")
 	client := gh()
-	if repositories, err := client.GetOrganizationRepositories(org); err == nil {
-		if security {
-			if err := client.LoadRepositorySecurityConfig(repositories...); err != nil {
-				panic(err)
-			}
+	repositories = github.FilterRepositories(repositories, repositoryFilters(cmd)...)
+	if security {
+		if err := client.LoadRepositorySecurityConfig(repositories...); err != nil {
+			panic(err)
 		}
-		if branchProtection {
-			if err := client.LoadRepositoryBranchProtectionRules(repositories...); err != nil {
-				panic(err)
-			}
+	}
+	if branchProtection {
+		if err := client.LoadRepositoryBranchProtectionRules(repositories...); err != nil {
+			panic(err)
 		}
-		if languages {
-			if err := client.LoadRepositoryLanguages(repositories...); err != nil {
-				panic(err)
-			}
+	}
+	if languages {
+		if err := client.LoadRepositoryLanguages(repositories...); err != nil {
+			panic(err)
 		}
-		if workflows {
-			if err := client.LoadRepositoryWorkflows(repositories...); err != nil {
-				panic(err)
-			}
+	}
+	if workflows {
+		if err := client.LoadRepositoryWorkflows(repositories...); err != nil {
+			panic(err)
 		}
-		core.PrintJSON(repositories)
+	}
+	if deployKeys {
+		if err := client.LoadRepositoryDeployKeys(repositories...); err != nil {
+			panic(err)
+		}
+	}
+	if secrets {
+		if err := client.LoadRepositoryActionsSecrets(repositories...); err != nil {
+			panic(err)
+		}
+	}
+	core.PrintJSON(repositories)
+}
+
+func executeOrganizationRepositoriesNotifyStale(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	client := gh()
+	audit, err := client.StaleRepositoryAudit(org, stalenessThresholds(cmd), repositoryFilters(cmd)...)
+	if err != nil {
+		panic(err)
+	}
+	if plan, err := client.StaleRepositoryNotificationPlan(audit); err == nil {
+		executePlan(cmd, plan)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationRepositoriesArchiveStale(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	gracePeriod, _ := cmd.Flags().GetInt("grace-period")
+	client := gh()
+	audit, err := client.StaleRepositoryAudit(org, stalenessThresholds(cmd), repositoryFilters(cmd)...)
+	if err != nil {
+		panic(err)
+	}
+	if plan, err := client.StaleRepositoryArchivalPlan(audit, time.Duration(gracePeriod)*24*time.Hour); err == nil {
+		executePlan(cmd, plan)
 	} else {
 		panic(err)
 	}
 }
 
+func executeOrganizationRepositoriesTopicsAdd(cmd *cobra.Command, args []string) {
+	planTopicChanges(cmd, "add-topics", args, func(r *github.Repository) error {
+		return gh().AddRepositoryTopics(r, args...)
+	})
+}
+
+func executeOrganizationRepositoriesTopicsRemove(cmd *cobra.Command, args []string) {
+	planTopicChanges(cmd, "remove-topics", args, func(r *github.Repository) error {
+		return gh().RemoveRepositoryTopics(r, args...)
+	})
+}
+
+func executeOrganizationRepositoriesTopicsReplace(cmd *cobra.Command, args []string) {
+	planTopicChanges(cmd, "replace-topics", args, func(r *github.Repository) error {
+		return gh().SetRepositoryTopics(r, args...)
+	})
+}
+
+func planTopicChanges(cmd *cobra.Command, operation string, topics []string, change func(r *github.Repository) error) {
+	org, _ := cmd.Flags().GetString("organization")
+	filters := repositoryFilters(cmd)
+	if len(filters) == 0 {
+		panic(fmt.Errorf("no repositories given, pass --repositories, --topic or --select"))
+	}
+	repositories, err := gh().GetOrganizationRepositories(org)
+	if err != nil {
+		panic(err)
+	}
+	plan := &github.Plan{}
+	for _, r := range github.FilterRepositories(repositories, filters...) {
+		r := r
+		// Archived repositories are read-only
+		if r.Archived {
+			continue
+		}
+		plan.Add(operation, r.Name, strings.Join(topics, ","), "", func() error {
+			return change(r)
+		})
+	}
+	executePlan(cmd, plan)
+}
+
 func executeOrganizationAuditFull(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if audit, err := gh().FullAudit(org); err == nil {
+	if audit, err := gh().FullAudit(org, repositoryFilters(cmd)...); err == nil {
 		core.PrintJSON(audit)
 	} else {
 		panic(err)
@@ -209,16 +1027,37 @@ func executeOrganizationAuditTeamMembership(cmd *cobra.Command, args []string) {
 
 func executeOrganizationAuditTeamPermission(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if audit, err := gh().TeamPermissionAudit(org); err == nil {
-		core.PrintJSON(audit)
-	} else {
+	format, _ := cmd.Flags().GetString("format")
+	if format == "json" {
+		if audit, err := gh().TeamPermissionAudit(org, repositoryFilters(cmd)...); err == nil {
+			core.PrintJSON(audit)
+		} else {
+			panic(err)
+		}
+		return
+	}
+	matrix, err := gh().TeamAccessMatrix(org, repositoryFilters(cmd)...)
+	if err != nil {
+		panic(err)
+	}
+	switch format {
+	case "csv":
+		err = matrix.WriteCSV(os.Stdout)
+	case "markdown", "md":
+		err = matrix.WriteMarkdown(os.Stdout)
+	case "html":
+		err = matrix.WriteHTML(os.Stdout)
+	default:
+		core.PrintJSON(matrix)
+	}
+	if err != nil {
 		panic(err)
 	}
 }
 
 func executeOrganizationAuditMemberPermission(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if audit, err := gh().MemberPermissionAudit(org); err == nil {
+	if audit, err := gh().MemberPermissionAudit(org, repositoryFilters(cmd)...); err == nil {
 		core.PrintJSON(audit)
 	} else {
 		panic(err)
@@ -227,20 +1066,320 @@ func executeOrganizationAuditMemberPermission(cmd *cobra.Command, args []string)
 
 func executeOrganizationAuditActions(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if audit, err := gh().ActionsAudit(org); err == nil {
+	if audit, err := gh().ActionsAudit(org, repositoryFilters(cmd)...); err == nil {
 		core.PrintJSON(audit)
 	} else {
 		panic(err)
 	}
 }
 
-func executeRepositoriesCreate(cmd *cobra.Command, args []string) {
-	var repo github.Repository
-	if err := core.ReadFromStdin(&repo); err != nil {
+func executeOrganizationAuditTeamPolicy(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if audit, err := gh().TeamPolicyAudit(org, readTeamPolicies(cmd), repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
 		panic(err)
 	}
+}
+
+func executeOrganizationAuditTeamHygiene(cmd *cobra.Command, args []string) {
 	org, _ := cmd.Flags().GetString("organization")
-	if err := gh().CreateRepository(org, &repo); err != nil {
+	if audit, err := gh().TeamHygieneAudit(org); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationAuditSecrets(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	maxAge, _ := cmd.Flags().GetInt("max-age")
+	if audit, err := gh().SecretAudit(org, maxAge, repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationAuditDeployKeys(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	var policy github.DeployKeyPolicy
+	policy.MaxAge, _ = cmd.Flags().GetInt("max-age")
+	policy.MinRSABits, _ = cmd.Flags().GetInt("min-rsa-bits")
+	if audit, err := gh().DeployKeyAudit(org, policy, repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationAuditWebhooks(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	var allowedHosts []string
+	if hosts, _ := cmd.Flags().GetString("allow-hosts"); hosts != "" {
+		allowedHosts = strings.Split(hosts, ",")
+	}
+	if audit, err := gh().WebhookAudit(org, allowedHosts, repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeOrganizationAuditStale(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if audit, err := gh().StaleRepositoryAudit(org, stalenessThresholds(cmd), repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func stalenessThresholds(cmd *cobra.Command) github.StalenessThresholds {
+	dormantAfter, _ := cmd.Flags().GetInt("dormant-after")
+	abandonedAfter, _ := cmd.Flags().GetInt("abandoned-after")
+	return github.StalenessThresholds{
+		DormantAfter:   dormantAfter,
+		AbandonedAfter: abandonedAfter,
+	}
+}
+
+func executeOrganizationAuditTwoFactor(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("organization")
+	if audit, err := gh().TwoFactorAudit(org, repositoryFilters(cmd)...); err == nil {
+		core.PrintJSON(audit)
+	} else {
+		panic(err)
+	}
+}
+
+func executeRepositoriesList(cmd *cobra.Command, args []string) {
+	user, _ := cmd.Flags().GetString("user")
+	affiliation, _ := cmd.Flags().GetString("affiliation")
+	if repositories, err := gh().GetUserRepositories(user, strings.Split(affiliation, ",")...); err == nil {
+		listRepositories(cmd, repositories)
+	} else {
+		panic(err)
+	}
+}
+
+func executeRepositoriesCreate(cmd *cobra.Command, args []string) {
+	repo := github.NewRepositoryCreation()
+	if err := core.ReadFromStdin(repo); err != nil {
+		panic(err)
+	}
+	owner, _ := cmd.Flags().GetString("user")
+	template, _ := cmd.Flags().GetString("template")
+	var created *github.Repository
+	var err error
+	if template != "" {
+		parts := strings.SplitN(template, "/", 2)
+		if len(parts) != 2 {
+			panic(fmt.Errorf("template must be given as owner/name, got %s", template))
+		}
+		if repo.Owner == "" {
+			repo.Owner = owner
+		}
+		if includeAll, _ := cmd.Flags().GetBool("include-all-branches"); includeAll {
+			repo.IncludeAllBranches = true
+		}
+		created, err = gh().CreateRepositoryFromTemplate(parts[0], parts[1], repo)
+	} else {
+		created, err = gh().CreateRepository(owner, repo)
+	}
+	if created != nil {
+		core.PrintJSON(created)
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+func executeRepositoriesUpdate(cmd *cobra.Command, args []string) {
+	owner, _ := cmd.Flags().GetString("user")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	filters := repositoryFilters(cmd)
+	var patches []github.RepositoryPatch
+	if len(filters) > 0 {
+		var patch github.RepositoryPatch
+		if err := core.ReadFromStdin(&patch); err != nil {
+			panic(err)
+		}
+		patches = append(patches, patch)
+	} else if err := core.ReadFromStdin(&patches); err != nil {
+		panic(err)
+	}
+	client := gh()
+	repositories, err := client.GetOwnerRepositories(owner)
+	if err != nil {
+		panic(err)
+	}
+	plan, err := client.RepositoryUpdatePlan(github.FilterRepositories(repositories, filters...), patches...)
+	if err != nil {
+		panic(err)
+	}
+	if !dryRun {
+		if err := plan.Execute(); err != nil {
+			core.PrintJSON(plan)
+			panic(err)
+		}
+	}
+	core.PrintJSON(plan)
+}
+
+func executeRepositoriesArchive(cmd *cobra.Command, args []string) {
+	executeLifecycle(cmd, "archive", selectRepositories(cmd, args), gh().ArchiveRepository)
+}
+
+func executeRepositoriesUnarchive(cmd *cobra.Command, args []string) {
+	executeLifecycle(cmd, "unarchive", selectRepositories(cmd, args), gh().UnarchiveRepository)
+}
+
+func executeRepositoriesRename(cmd *cobra.Command, args []string) {
+	executeLifecycle(cmd, "rename to "+args[1], selectRepositories(cmd, args[:1]), func(r *github.Repository) (*github.ManifestEntry, error) {
+		return gh().RenameRepository(r, args[1])
+	})
+}
+
+func executeRepositoriesTransfer(cmd *cobra.Command, args []string) {
+	to, _ := cmd.Flags().GetString("to")
+	mapping := make(map[string]string)
+	if teamMapping, _ := cmd.Flags().GetString("team-mapping"); teamMapping != "" {
+		for _, pair := range strings.Split(teamMapping, ",") {
+			parts := strings.SplitN(pair, "=", 2)
+			if len(parts) != 2 {
+				panic(fmt.Errorf("invalid team mapping %s, expected source=target", pair))
+			}
+			mapping[parts[0]] = parts[1]
+		}
+	}
+	executeLifecycle(cmd, "transfer to "+to, selectRepositories(cmd, args), func(r *github.Repository) (*github.ManifestEntry, error) {
+		return gh().TransferRepository(r, to, mapping)
+	})
+}
+
+func executeRepositoriesDelete(cmd *cobra.Command, args []string) {
+	executeLifecycle(cmd, "delete", selectRepositories(cmd, args), gh().DeleteRepository)
+}
+
+func executeRepositoriesRenameDefaultBranch(cmd *cobra.Command, args []string) {
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	client := gh()
+	plan := &github.Plan{}
+	var renames []*github.BranchRename
+	for _, r := range selectRepositories(cmd, args) {
+		r := r
+		if r.DefaultBranch != from || r.Archived {
+			continue
+		}
+		plan.Add("rename-default-branch", r.Name, from+" to "+to, "", func() error {
+			rename, err := client.RenameDefaultBranch(r, to)
+			if rename != nil {
+				renames = append(renames, rename)
+			}
+			return err
+		})
+	}
+	if !confirm {
+		logging.L().Warn().Msg("Nothing done, pass --confirm to carry out the planned operations")
+		core.PrintJSON(plan)
+		return
+	}
+	err := plan.Execute()
+	core.PrintJSON(renames)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// selectRepositories returns the repositories of the user given by name or matching the selector. It refuses to
+// select all repositories if neither is given.
+func selectRepositories(cmd *cobra.Command, names []string) []*github.Repository {
+	owner, _ := cmd.Flags().GetString("user")
+	filters := repositoryFilters(cmd)
+	if len(names) == 0 && len(filters) == 0 {
+		panic(fmt.Errorf("no repositories given, pass their names or a selector"))
+	}
+	repositories, err := gh().GetOwnerRepositories(owner)
+	if err != nil {
+		panic(err)
+	}
+	repositories = github.FilterRepositories(repositories, filters...)
+	if len(names) == 0 {
+		return repositories
+	}
+	var selected []*github.Repository
+	for _, name := range names {
+		found := false
+		for _, r := range repositories {
+			if strings.EqualFold(r.Name, name) {
+				selected = append(selected, r)
+				found = true
+			}
+		}
+		if !found {
+			panic(fmt.Errorf("repository %s/%s does not exist or does not match the selector", owner, name))
+		}
+	}
+	return selected
+}
+
+// executeLifecycle runs the operation on all repositories and appends what was done to the manifest file. Operations
+// are only planned unless the --confirm flag is given.
+func executeLifecycle(cmd *cobra.Command, detail string, repositories []*github.Repository, operation func(r *github.Repository) (*github.ManifestEntry, error)) {
+	confirm, _ := cmd.Flags().GetBool("confirm")
+	manifest, _ := cmd.Flags().GetString("manifest")
+	plan := &github.Plan{}
+	var entries []*github.ManifestEntry
+	for _, r := range repositories {
+		r := r
+		plan.Add(cmd.Name(), r.Owner+"/"+r.Name, detail, "", func() error {
+			entry, err := operation(r)
+			if entry != nil {
+				entries = append(entries, entry)
+			}
+			return err
+		})
+	}
+	if !confirm {
+		logging.L().Warn().Msg("Nothing done, pass --confirm to carry out the planned operations")
+		core.PrintJSON(plan)
+		return
+	}
+	err := plan.Execute()
+	if manifestErr := github.AppendManifest(manifest, entries...); manifestErr != nil {
+		panic(manifestErr)
+	}
+	core.PrintJSON(entries)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func executeRepositoriesCodeOwnersCheck(cmd *cobra.Command, args []string) {
+	org, _ := cmd.Flags().GetString("user")
+	client := gh()
+	repositories, err := client.GetOrganizationRepositories(org)
+	if err != nil {
+		panic(err)
+	}
+	repositories = github.FilterRepositories(repositories, repositoryFilters(cmd)...)
+	if len(args) > 0 {
+		var selected []*github.Repository
+		for _, r := range repositories {
+			for _, name := range args {
+				if r.Name == name {
+					selected = append(selected, r)
+				}
+			}
+		}
+		repositories = selected
+	}
+	if issues, err := client.CheckCodeOwners(org, repositories...); err == nil {
+		core.PrintJSON(issues)
+	} else {
 		panic(err)
 	}
 }
@@ -249,6 +1388,62 @@ func executeUserKeysList(cmd *cobra.Command, args []string) {
 
 }
 
+// repositorySelectionFlags adds the --topic and --select flags read by repositoryFilters.
+func repositorySelectionFlags() Applicant {
+	topic := Flag("topic", Str(""), Description("Only include Repositories with this topic"))
+	selector := Flag("select", Str(""), Description("Only include Repositories matching this selector (e.g. name=api-*,archived=false,pushed_before=180d)"))
+	return func(builder *Command) {
+		topic(builder)
+		selector(builder)
+	}
+}
+
+// repositoryFilters builds the Repository selection from the flags defined on the command.
+func repositoryFilters(cmd *cobra.Command) []github.RepositoryFilter {
+	var filters []github.RepositoryFilter
+	if pattern, _ := cmd.Flags().GetString("pattern"); pattern != "" {
+		filter, err := github.WithName(pattern)
+		if err != nil {
+			panic(err)
+		}
+		filters = append(filters, filter)
+	}
+	if selector, _ := cmd.Flags().GetString("select"); selector != "" {
+		selected, err := github.ParseRepositorySelector(selector)
+		if err != nil {
+			panic(err)
+		}
+		filters = append(filters, selected...)
+	}
+	if topic, _ := cmd.Flags().GetString("topic"); topic != "" {
+		filters = append(filters, github.WithTopic(topic))
+	}
+	if names, _ := cmd.Flags().GetString("repositories"); names != "" {
+		selected := strings.Split(names, ",")
+		filters = append(filters, func(r *github.Repository) bool {
+			for _, name := range selected {
+				if r.Name == strings.TrimSpace(name) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	return filters
+}
+
+// executePlan only prints the plan unless execution was explicitly requested using the --execute flag.
+func executePlan(cmd *cobra.Command, plan *github.Plan) {
+	execute, _ := cmd.Flags().GetBool("execute")
+	if execute {
+		if err := plan.Execute(); err != nil {
+			core.PrintJSON(plan)
+			panic(err)
+		}
+	}
+	core.PrintJSON(plan)
+}
+
 func gh() *github.GithubClient {
 	if githubClient == nil {
 		githubClient = github.New(viper.GetString("token"))
@@ -0,0 +1,274 @@
+package github
+
+import (
+	"bufio"
+	"context"
+	"regexp"
+	"strings"
+
+	log "github.com/mtrense/soil/logging"
+	"github.com/shurcooL/githubv4"
+)
+
+type CodeOwners struct {
+	Path  string           `json:"path,omitempty"`
+	Rules []CodeOwnersRule `json:"rules,omitempty"`
+}
+
+type CodeOwnersRule struct {
+	Line    int      `json:"line,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+	Owners  []string `json:"owners,omitempty"`
+}
+
+var (
+	codeOwnersUser  = regexp.MustCompile(`^@[A-Za-z0-9-]+$`)
+	codeOwnersTeam  = regexp.MustCompile(`^@[A-Za-z0-9-]+/[A-Za-z0-9_.-]+$`)
+	codeOwnersEmail = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+)
+
+// GetCodeOwners fetches the CODEOWNERS file of the repository from the locations Github considers, in the order of
+// precedence Github uses. It returns nil if the repository has no CODEOWNERS file.
+func (s *GithubClient) GetCodeOwners(owner string, repository string) (*CodeOwners, error) {
+	type blob struct {
+		Blob struct {
+			Text githubv4.String
+		} `graphql:"... on Blob"`
+	}
+	var query struct {
+		Repository struct {
+			Github blob `graphql:"github: object(expression: \"HEAD:.github/CODEOWNERS\")"`
+			Root   blob `graphql:"root: object(expression: \"HEAD:CODEOWNERS\")"`
+			Docs   blob `graphql:"docs: object(expression: \"HEAD:docs/CODEOWNERS\")"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := s.Query(&query).Str("owner", owner).Str("repo", repository).Run(); err != nil {
+		return nil, err
+	}
+	candidates := []struct {
+		path    string
+		content githubv4.String
+	}{
+		{".github/CODEOWNERS", query.Repository.Github.Blob.Text},
+		{"CODEOWNERS", query.Repository.Root.Blob.Text},
+		{"docs/CODEOWNERS", query.Repository.Docs.Blob.Text},
+	}
+	for _, c := range candidates {
+		if c.content != "" {
+			return &CodeOwners{
+				Path:  c.path,
+				Rules: ParseCodeOwners(string(c.content)),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file. Comments and empty lines are skipped.
+func ParseCodeOwners(content string) []CodeOwnersRule {
+	var rules []CodeOwnersRule
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		rules = append(rules, CodeOwnersRule{
+			Line:    line,
+			Pattern: fields[0],
+			Owners:  fields[1:],
+		})
+	}
+	return rules
+}
+
+// Regexp translates the pattern of the rule into a regular expression matching repository paths, following the
+// gitignore rules that CODEOWNERS builds upon. It returns nil for patterns CODEOWNERS does not support.
+func (s CodeOwnersRule) Regexp() *regexp.Regexp {
+	pattern := s.Pattern
+	if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") {
+		return nil
+	}
+	directory := strings.HasSuffix(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(strings.TrimSuffix(pattern, "/"), "/")
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	var sb strings.Builder
+	if anchored {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("^(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	// A pattern matching a directory also matches everything below it. Patterns ending in / only match directories,
+	// while a wildcard in the last segment only matches entries of a single directory.
+	switch {
+	case directory:
+		sb.WriteString("/.*$")
+	case !strings.ContainsAny(last, "*?"):
+		sb.WriteString("(/.*)?$")
+	default:
+		sb.WriteString("$")
+	}
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+type CodeOwnersIssue struct {
+	Repository string `json:"repository,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Pattern    string `json:"pattern,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Problem    string `json:"problem,omitempty"`
+}
+
+// CheckCodeOwners validates the CODEOWNERS files of the given repositories of the organization. Referenced teams must
+// exist and have write access to the repository, referenced users must be members of the organization with write
+// access, and every pattern must match at least one file on the default branch.
+func (s *GithubClient) CheckCodeOwners(org string, repositories ...*Repository) ([]CodeOwnersIssue, error) {
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.LoadTeamRepositories(org, teams...); err != nil {
+		return nil, err
+	}
+	teamAccess := make(map[string]map[string]int)
+	for _, root := range BuildTeamTree(teams) {
+		root.Walk(func(team *TeamNode, depth int) {
+			access := make(map[string]int)
+			for _, r := range team.Repositories {
+				access[strings.ToLower(r.Name)] = permissionRank(r.Permission)
+			}
+			for _, r := range team.InheritedRepositories {
+				access[strings.ToLower(r.Name)] = permissionRank(r.Permission)
+			}
+			teamAccess[strings.ToLower(team.Slug)] = access
+		})
+	}
+	members, err := s.GetMembers(org)
+	if err != nil {
+		return nil, err
+	}
+	isMember := make(map[string]bool)
+	for _, m := range members {
+		isMember[strings.ToLower(m.Login)] = !m.Pending
+	}
+	var issues []CodeOwnersIssue
+	for _, repository := range repositories {
+		if repository.Archived {
+			continue
+		}
+		codeOwners, err := s.GetCodeOwners(repository.Owner, repository.Name)
+		if err != nil {
+			return nil, err
+		}
+		if codeOwners == nil {
+			log.L().Info().Str("repo", repository.Name).Msg("No CODEOWNERS file")
+			continue
+		}
+		if err := s.LoadRepositoryCollaborators(repository); err != nil {
+			return nil, err
+		}
+		userAccess := make(map[string]int)
+		for _, c := range repository.Collaborators {
+			userAccess[strings.ToLower(c.Login)] = permissionRank(c.EffectivePermission)
+		}
+		files, complete, err := s.listRepositoryFiles(repository.Owner, repository.Name, repository.DefaultBranch)
+		if err != nil {
+			return nil, err
+		}
+		issue := func(rule CodeOwnersRule, owner, problem string) {
+			issues = append(issues, CodeOwnersIssue{
+				Repository: repository.Name,
+				Path:       codeOwners.Path,
+				Line:       rule.Line,
+				Pattern:    rule.Pattern,
+				Owner:      owner,
+				Problem:    problem,
+			})
+		}
+		for _, rule := range codeOwners.Rules {
+			if re := rule.Regexp(); re == nil {
+				issue(rule, "", "unsupported pattern")
+			} else if complete && !matchesAny(re, files) {
+				issue(rule, "", "pattern matches no files")
+			}
+			for _, owner := range rule.Owners {
+				switch {
+				case codeOwnersTeam.MatchString(owner):
+					parts := strings.SplitN(strings.TrimPrefix(owner, "@"), "/", 2)
+					access, exists := teamAccess[strings.ToLower(parts[1])]
+					if !strings.EqualFold(parts[0], org) || !exists {
+						issue(rule, owner, "team does not exist")
+					} else if access[strings.ToLower(repository.Name)] < permissionRank("WRITE") {
+						issue(rule, owner, "team has no write access")
+					}
+				case codeOwnersUser.MatchString(owner):
+					login := strings.ToLower(strings.TrimPrefix(owner, "@"))
+					if !isMember[login] {
+						issue(rule, owner, "user is not a member of the organization")
+					}
+					if userAccess[login] < permissionRank("WRITE") {
+						issue(rule, owner, "user has no write access")
+					}
+				case codeOwnersEmail.MatchString(owner):
+					// Email addresses can not be resolved to accounts using the API
+				default:
+					issue(rule, owner, "invalid owner")
+				}
+			}
+		}
+	}
+	return issues, nil
+}
+
+func matchesAny(re *regexp.Regexp, paths []string) bool {
+	for _, p := range paths {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// listRepositoryFiles returns the paths of all files on the given ref. The second return value is false if the
+// repository is too large for the list to be complete.
+func (s *GithubClient) listRepositoryFiles(owner string, repository string, ref string) ([]string, bool, error) {
+	tree, _, err := s.v3Client.Git.GetTree(context.Background(), owner, repository, ref, true)
+	if err != nil {
+		return nil, false, err
+	}
+	var files []string
+	for _, entry := range tree.Entries {
+		if entry.GetType() == "blob" {
+			files = append(files, entry.GetPath())
+		}
+	}
+	return files, !tree.GetTruncated(), nil
+}
@@ -0,0 +1,62 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("CODEOWNERS", func() {
+	It("parses rules and skips comments and empty lines", func() {
+		rules := ParseCodeOwners(`# Owners of this repository
+
+*       @org/everyone
+/docs/  @org/writers docs@example.com # reviewed by the writers
+  # indented comment
+*.go @alice
+`)
+		Expect(rules).To(Equal([]CodeOwnersRule{
+			{Line: 3, Pattern: "*", Owners: []string{"@org/everyone"}},
+			{Line: 4, Pattern: "/docs/", Owners: []string{"@org/writers", "docs@example.com"}},
+			{Line: 6, Pattern: "*.go", Owners: []string{"@alice"}},
+		}))
+	})
+
+	It("keeps rules without owners", func() {
+		Expect(ParseCodeOwners("/vendor/")).To(Equal([]CodeOwnersRule{{Line: 1, Pattern: "/vendor/", Owners: []string{}}}))
+	})
+
+	DescribeTable("matches paths like Github does",
+		func(pattern string, path string, matches bool) {
+			re := CodeOwnersRule{Pattern: pattern}.Regexp()
+			Expect(re).NotTo(BeNil())
+			Expect(re.MatchString(path)).To(Equal(matches))
+		},
+		Entry("*.js in the root", "*.js", "index.js", true),
+		Entry("*.js in any directory", "*.js", "src/app/index.js", true),
+		Entry("*.js not below a matching directory", "*.js", "lib.js/index.ts", false),
+		Entry("/docs/* directly in docs", "/docs/*", "docs/index.md", true),
+		Entry("/docs/* not in subdirectories", "/docs/*", "docs/build/index.md", false),
+		Entry("/docs/* not in other docs directories", "/docs/*", "src/docs/index.md", false),
+		Entry("docs/ in any docs directory", "docs/", "src/docs/index.md", true),
+		Entry("docs/ in subdirectories", "docs/", "docs/build/index.md", true),
+		Entry("docs/ not a file named docs", "docs/", "docs", false),
+		Entry("**/logs in the root", "**/logs", "logs/today.log", true),
+		Entry("**/logs in any directory", "**/logs", "build/logs/today.log", true),
+		Entry("**/logs not a prefix", "**/logs", "build/logsfile", false),
+		Entry("apps/** in subdirectories", "apps/**", "apps/web/main.go", true),
+		Entry("apps/** only in the root", "apps/**", "src/apps/main.go", false),
+		Entry("a file name anywhere", "Makefile", "build/Makefile", true),
+		Entry("an anchored path and everything below it", "/src/api", "src/api/main.go", true),
+	)
+
+	DescribeTable("does not support negation and character ranges",
+		func(pattern string) {
+			Expect(CodeOwnersRule{Pattern: pattern}.Regexp()).To(BeNil())
+		},
+		Entry("negation", "!*.md"),
+		Entry("character range", "*.[ch]"),
+	)
+})
@@ -0,0 +1,233 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+// RepositoryCreation describes a repository to be created along with the settings applied right after creation.
+type RepositoryCreation struct {
+	Repository
+	LicenseTemplate   string `json:"license_template,omitempty"`
+	GitignoreTemplate string `json:"gitignore_template,omitempty"`
+	AutoInit          bool   `json:"auto_init,omitempty"`
+	// IncludeAllBranches copies all branches of the template instead of only its default branch.
+	IncludeAllBranches bool `json:"include_all_branches,omitempty"`
+	// Teams maps team slugs to the permission the team is granted on the new repository.
+	Teams            map[string]string     `json:"teams,omitempty"`
+	BranchProtection *BranchProtectionRule `json:"branch_protection,omitempty"`
+}
+
+// NewRepositoryCreation returns a RepositoryCreation preset with the defaults Github uses for new repositories, so
+// that settings not explicitly given are not disabled.
+func NewRepositoryCreation() *RepositoryCreation {
+	return &RepositoryCreation{
+		Repository: Repository{
+			IssuesEnabled:    true,
+			WikiEnabled:      true,
+			ProjectsEnabled:  true,
+			AllowMergeCommit: true,
+			AllowSquashMerge: true,
+			AllowRebaseMerge: true,
+		},
+	}
+}
+
+// CreateRepository creates the repository for the given owner, which is either an organization or the authenticated
+// user, and applies topics, team permissions and the branch protection rule afterwards.
+func (s *GithubClient) CreateRepository(owner string, r *RepositoryCreation) (*Repository, error) {
+	org, err := s.organizationOrViewer(owner)
+	if err != nil {
+		return nil, err
+	}
+	result, _, err := s.v3Client.Repositories.Create(context.Background(), org, &gh3.Repository{
+		Name:                &r.Name,
+		Private:             &r.Private,
+		Description:         boxString(r.Description),
+		Homepage:            boxString(r.Homepage),
+		HasIssues:           &r.IssuesEnabled,
+		HasWiki:             &r.WikiEnabled,
+		HasProjects:         &r.ProjectsEnabled,
+		IsTemplate:          &r.Template,
+		AllowMergeCommit:    &r.AllowMergeCommit,
+		AllowSquashMerge:    &r.AllowSquashMerge,
+		AllowRebaseMerge:    &r.AllowRebaseMerge,
+		DeleteBranchOnMerge: &r.DeleteBranchOnMerge,
+		AutoInit:            &r.AutoInit,
+		LicenseTemplate:     boxString(r.LicenseTemplate),
+		GitignoreTemplate:   boxString(r.GitignoreTemplate),
+	})
+	if err != nil {
+		return nil, err
+	}
+	repository := fromGh3Repository(result)
+	if err := s.applyRepositoryCreation(repository, result.GetNodeID(), r); err != nil {
+		return repository, err
+	}
+	return repository, nil
+}
+
+// CreateRepositoryFromTemplate creates the target repository from the given template repository. As Github only
+// takes over name, description and visibility from the request, all other settings of target are applied afterwards.
+func (s *GithubClient) CreateRepositoryFromTemplate(templateOwner string, templateName string, target *RepositoryCreation) (*Repository, error) {
+	request := struct {
+		Owner              string `json:"owner,omitempty"`
+		Name               string `json:"name"`
+		Description        string `json:"description,omitempty"`
+		Private            bool   `json:"private"`
+		IncludeAllBranches bool   `json:"include_all_branches"`
+	}{
+		Owner:              target.Owner,
+		Name:               target.Name,
+		Description:        target.Description,
+		Private:            target.Private,
+		IncludeAllBranches: target.IncludeAllBranches,
+	}
+	req, err := s.v3Client.NewRequest("POST", fmt.Sprintf("repos/%v/%v/generate", templateOwner, templateName), request)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.baptiste-preview+json")
+	var generated gh3.Repository
+	if _, err := s.v3Client.Do(context.Background(), req, &generated); err != nil {
+		return nil, err
+	}
+	result, _, err := s.v3Client.Repositories.Edit(context.Background(), generated.GetOwner().GetLogin(), generated.GetName(), &gh3.Repository{
+		Homepage:            boxString(target.Homepage),
+		HasIssues:           &target.IssuesEnabled,
+		HasWiki:             &target.WikiEnabled,
+		HasProjects:         &target.ProjectsEnabled,
+		IsTemplate:          &target.Template,
+		AllowMergeCommit:    &target.AllowMergeCommit,
+		AllowSquashMerge:    &target.AllowSquashMerge,
+		AllowRebaseMerge:    &target.AllowRebaseMerge,
+		DeleteBranchOnMerge: &target.DeleteBranchOnMerge,
+	})
+	if err != nil {
+		return fromGh3Repository(&generated), err
+	}
+	repository := fromGh3Repository(result)
+	if err := s.applyRepositoryCreation(repository, result.GetNodeID(), target); err != nil {
+		return repository, err
+	}
+	return repository, nil
+}
+
+// applyRepositoryCreation applies the settings of r that can only be set once the repository exists.
+func (s *GithubClient) applyRepositoryCreation(repository *Repository, nodeID string, r *RepositoryCreation) error {
+	if len(r.Topics) > 0 {
+		if err := s.SetRepositoryTopics(repository, r.Topics...); err != nil {
+			return err
+		}
+	}
+	for slug, permission := range r.Teams {
+		if err := s.GrantTeamRepository(repository.Owner, slug, repository.Owner, repository.Name, permission); err != nil {
+			return fmt.Errorf("granting %s to team %s: %w", permission, slug, err)
+		}
+	}
+	if r.BranchProtection != nil {
+		rule := *r.BranchProtection
+		if rule.Pattern == "" {
+			rule.Pattern = repository.DefaultBranch
+		}
+		if err := s.CreateBranchProtectionRule(nodeID, rule); err != nil {
+			return err
+		}
+		repository.BranchProtectionRules = append(repository.BranchProtectionRules, rule)
+	}
+	return nil
+}
+
+// CreateBranchProtectionRuleInput extends githubv4.CreateBranchProtectionRuleInput by the fields missing in the pinned
+// githubv4 version. The name has to match the GraphQL input type, as it is used as the type of the mutation variable.
+type CreateBranchProtectionRuleInput struct {
+	githubv4.CreateBranchProtectionRuleInput
+	AllowsForcePushes     *githubv4.Boolean `json:"allowsForcePushes,omitempty"`
+	AllowsDeletions       *githubv4.Boolean `json:"allowsDeletions,omitempty"`
+	RequiresLinearHistory *githubv4.Boolean `json:"requiresLinearHistory,omitempty"`
+}
+
+// CreateBranchProtectionRule creates the rule on the repository with the given node id.
+func (s *GithubClient) CreateBranchProtectionRule(repositoryID string, rule BranchProtectionRule) error {
+	input := CreateBranchProtectionRuleInput{
+		CreateBranchProtectionRuleInput: githubv4.CreateBranchProtectionRuleInput{
+			RepositoryID:                 githubv4.ID(repositoryID),
+			Pattern:                      githubv4.String(rule.Pattern),
+			RequiresApprovingReviews:     githubv4.NewBoolean(githubv4.Boolean(rule.RequiresApprovingReviews)),
+			RequiredApprovingReviewCount: githubv4.NewInt(githubv4.Int(rule.RequiredApprovingReviewCount)),
+			RequiresCommitSignatures:     githubv4.NewBoolean(githubv4.Boolean(rule.RequiresCommitSignatures)),
+			IsAdminEnforced:              githubv4.NewBoolean(githubv4.Boolean(rule.IsAdminEnforced)),
+			RequiresStatusChecks:         githubv4.NewBoolean(githubv4.Boolean(len(rule.RequiredStatusCheckContexts) > 0)),
+			RequiresStrictStatusChecks:   githubv4.NewBoolean(githubv4.Boolean(rule.RequiresStrictStatusChecks)),
+			RequiresCodeOwnerReviews:     githubv4.NewBoolean(githubv4.Boolean(rule.RequiresCodeOwnerReviews)),
+			DismissesStaleReviews:        githubv4.NewBoolean(githubv4.Boolean(rule.DismissesStaleReviews)),
+			RestrictsReviewDismissals:    githubv4.NewBoolean(githubv4.Boolean(rule.RestrictsReviewDismissals)),
+		},
+		AllowsForcePushes:     githubv4.NewBoolean(githubv4.Boolean(rule.AllowsForcePushes)),
+		AllowsDeletions:       githubv4.NewBoolean(githubv4.Boolean(rule.AllowsDeletions)),
+		RequiresLinearHistory: githubv4.NewBoolean(githubv4.Boolean(rule.RequiresLinearHistory)),
+	}
+	if len(rule.RequiredStatusCheckContexts) > 0 {
+		var contexts []githubv4.String
+		for _, c := range rule.RequiredStatusCheckContexts {
+			contexts = append(contexts, githubv4.String(c))
+		}
+		input.RequiredStatusCheckContexts = &contexts
+	}
+	var mutation struct {
+		CreateBranchProtectionRule struct {
+			ClientMutationID githubv4.String
+		} `graphql:"createBranchProtectionRule(input: $input)"`
+	}
+	return s.v4Client.Mutate(context.Background(), &mutation, input, nil)
+}
+
+// organizationOrViewer returns the given owner, or an empty string if it is the authenticated user, as the v3 API
+// distinguishes between repositories of organizations and of the authenticated user.
+func (s *GithubClient) organizationOrViewer(owner string) (string, error) {
+	viewer, _, err := s.v3Client.Users.Get(context.Background(), "")
+	if err != nil {
+		return "", err
+	}
+	if strings.EqualFold(viewer.GetLogin(), owner) {
+		return "", nil
+	}
+	return owner, nil
+}
+
+func fromGh3Repository(r *gh3.Repository) *Repository {
+	return &Repository{
+		Owner:               r.GetOwner().GetLogin(),
+		Name:                r.GetName(),
+		Private:             r.GetPrivate(),
+		Description:         r.GetDescription(),
+		Homepage:            r.GetHomepage(),
+		URL:                 r.GetHTMLURL(),
+		Ssh:                 r.GetSSHURL(),
+		Git:                 r.GetGitURL(),
+		DefaultBranch:       r.GetDefaultBranch(),
+		Archived:            r.GetArchived(),
+		Disabled:            r.GetDisabled(),
+		Template:            r.GetIsTemplate(),
+		Fork:                r.GetFork(),
+		IssuesEnabled:       r.GetHasIssues(),
+		WikiEnabled:         r.GetHasWiki(),
+		ProjectsEnabled:     r.GetHasProjects(),
+		PagesEnabled:        r.GetHasPages(),
+		AllowRebaseMerge:    r.GetAllowRebaseMerge(),
+		AllowSquashMerge:    r.GetAllowSquashMerge(),
+		AllowMergeCommit:    r.GetAllowMergeCommit(),
+		DeleteBranchOnMerge: r.GetDeleteBranchOnMerge(),
+		LicenseName:         r.GetLicense().GetName(),
+		Topics:              r.Topics,
+		DiskUsage:           r.GetSize(),
+		PushedAt:            r.GetPushedAt().Time,
+		UpdatedAt:           r.GetUpdatedAt().Time,
+		PrimaryLanguage:     r.GetLanguage(),
+	}
+}
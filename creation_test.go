@@ -0,0 +1,48 @@
+package github_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("CreateBranchProtectionRule", func() {
+	var server *httptest.Server
+	var request struct {
+		Query     string
+		Variables map[string]map[string]interface{}
+	}
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+			w.Write([]byte(`{"data": {"createBranchProtectionRule": {"clientMutationId": ""}}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("sends force pushes, deletions and linear history", func() {
+		err := NewTestClient(server.URL).CreateBranchProtectionRule("R_1", BranchProtectionRule{
+			Pattern:               "main",
+			AllowsForcePushes:     true,
+			AllowsDeletions:       true,
+			RequiresLinearHistory: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(request.Query).To(ContainSubstring("$input:CreateBranchProtectionRuleInput!"))
+		input := request.Variables["input"]
+		Expect(input).To(HaveKeyWithValue("repositoryId", "R_1"))
+		Expect(input).To(HaveKeyWithValue("pattern", "main"))
+		Expect(input).To(HaveKeyWithValue("allowsForcePushes", true))
+		Expect(input).To(HaveKeyWithValue("allowsDeletions", true))
+		Expect(input).To(HaveKeyWithValue("requiresLinearHistory", true))
+	})
+})
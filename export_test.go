@@ -0,0 +1,21 @@
+package github
+
+import (
+	"net/url"
+
+	gh3 "github.com/google/go-github/v32/github"
+	gh4 "github.com/shurcooL/githubv4"
+)
+
+// NewTestClient returns a client talking to the fake Github at baseURL, serving the REST API at its root and the
+// GraphQL API at /graphql.
+func NewTestClient(baseURL string) *GithubClient {
+	v3Client := gh3.NewClient(nil)
+	v3Client.BaseURL, _ = url.Parse(baseURL + "/")
+	v4Client := gh4.NewEnterpriseClient(baseURL+"/graphql", nil)
+	return &GithubClient{
+		v4Client:        v4Client,
+		v4PreviewClient: v4Client,
+		v3Client:        v3Client,
+	}
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"net/http"
 
 	gh3 "github.com/google/go-github/v32/github"
 	gh4 "github.com/shurcooL/githubv4"
@@ -10,7 +11,9 @@ import (
 
 type GithubClient struct {
 	v4Client *gh4.Client
-	v3Client *gh3.Client
+	// v4PreviewClient is used for queries that rely on the dependency graph preview of the GraphQL schema
+	v4PreviewClient *gh4.Client
+	v3Client        *gh3.Client
 }
 
 func New(token string) *GithubClient {
@@ -20,14 +23,26 @@ func New(token string) *GithubClient {
 	httpClient := oauth2.NewClient(context.Background(), src)
 
 	return &GithubClient{
-		v4Client: gh4.NewClient(httpClient),
-		v3Client: gh3.NewClient(httpClient),
+		v4Client:        gh4.NewClient(httpClient),
+		v4PreviewClient: gh4.NewClient(&http.Client{Transport: previewTransport{httpClient.Transport}}),
+		v3Client:        gh3.NewClient(httpClient),
 	}
 }
 
+// previewTransport enables the dependency graph preview of the GraphQL schema.
+type previewTransport struct {
+	base http.RoundTripper
+}
+
+func (s previewTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept", "application/vnd.github.hawkgirl-preview+json")
+	return s.base.RoundTrip(req)
+}
+
 func (s *GithubClient) paginateGithub3(fetcher func(lo gh3.ListOptions) (*gh3.Response, error)) error {
 	listOptions := gh3.ListOptions{
-		Page:    0,
+		Page:    1,
 		PerPage: 100,
 	}
 	for {
@@ -38,7 +53,7 @@ func (s *GithubClient) paginateGithub3(fetcher func(lo gh3.ListOptions) (*gh3.Re
 		if resp.NextPage == 0 {
 			break
 		}
-		listOptions.Page += 1
+		listOptions.Page = resp.NextPage
 	}
 	return nil
 }
@@ -11,3 +11,11 @@ func TestGithub(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Github Suite")
 }
+
+func str(s string) *string { return &s }
+
+func boolean(b bool) *bool { return &b }
+
+func number(i int) *int { return &i }
+
+func id(i int64) *int64 { return &i }
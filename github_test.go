@@ -0,0 +1,46 @@
+package github_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("Pagination", func() {
+	var server *httptest.Server
+	var requested []string
+
+	BeforeEach(func() {
+		requested = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("page")
+			requested = append(requested, page)
+			n, _ := strconv.Atoi(page)
+			if n < 3 {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, server.URL, r.URL.Path, n+1))
+			}
+			fmt.Fprintf(w, `[{"login": "user-%s"}]`, page)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("requests every page exactly once", func() {
+		blocked, err := NewTestClient(server.URL).GetBlockedUsers("org")
+		Expect(err).NotTo(HaveOccurred())
+		var logins []string
+		for _, u := range blocked {
+			logins = append(logins, u.Login)
+		}
+		Expect(requested).To(Equal([]string{"1", "2", "3"}))
+		Expect(logins).To(Equal([]string{"user-1", "user-2", "user-3"}))
+	})
+})
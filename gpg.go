@@ -0,0 +1,49 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+type GPGKey struct {
+	ID        *int64     `json:"id,omitempty"`
+	KeyID     *string    `json:"key_id,omitempty"`
+	Emails    []string   `json:"emails,omitempty"`
+	CanSign   *bool      `json:"can_sign,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func fromGh3GPGKey(key *gh3.GPGKey) GPGKey {
+	// Signing is often done with a subkey, so the key counts as signing key if any of its subkeys can sign
+	canSign := key.GetCanSign()
+	for _, sub := range key.Subkeys {
+		canSign = canSign || sub.GetCanSign()
+	}
+	var emails []string
+	for _, e := range key.Emails {
+		emails = append(emails, e.GetEmail())
+	}
+	return GPGKey{
+		ID:        key.ID,
+		KeyID:     key.KeyID,
+		Emails:    emails,
+		CanSign:   &canSign,
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+	}
+}
+
+func (s *GithubClient) ListGPGKeys(user string) ([]GPGKey, error) {
+	var result []GPGKey
+	err := s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		keys, resp, err := s.v3Client.Users.ListGPGKeys(context.Background(), user, &lo)
+		for _, key := range keys {
+			result = append(result, fromGh3GPGKey(key))
+		}
+		return resp, err
+	})
+	return result, err
+}
@@ -1,5 +1,7 @@
 package github
 
+import "strings"
+
 func unboxString(s *string) string {
 	if s == nil {
 		return ""
@@ -20,3 +22,41 @@ func boxString(s string) *string {
 	}
 	return &s
 }
+
+// permissionRank orders repository permissions as returned by both the v3 and v4 API, so that they can be compared.
+// Unknown permissions rank lowest.
+func permissionRank(permission string) int {
+	switch strings.ToUpper(permission) {
+	case "READ", "PULL":
+		return 1
+	case "TRIAGE":
+		return 2
+	case "WRITE", "PUSH":
+		return 3
+	case "MAINTAIN":
+		return 4
+	case "ADMIN":
+		return 5
+	}
+	return 0
+}
+
+// restPermission translates a repository permission as returned by the v4 API into the form expected by the v3 API.
+func restPermission(permission string) string {
+	switch strings.ToUpper(permission) {
+	case "READ":
+		return "pull"
+	case "WRITE":
+		return "push"
+	}
+	return strings.ToLower(permission)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package github
+
+import "github.com/shurcooL/githubv4"
+
+type ExternalIdentity struct {
+	GUID         string   `json:"guid,omitempty"`
+	NameID       string   `json:"name_id,omitempty"`
+	ScimUsername string   `json:"scim_username,omitempty"`
+	Emails       []string `json:"emails,omitempty"`
+	Login        string   `json:"login,omitempty"`
+}
+
+// GetExternalIdentities returns all identities known to the SAML identity provider of the organization. The second
+// return value is false if the organization has no SAML identity provider configured.
+func (s *GithubClient) GetExternalIdentities(org string) ([]*ExternalIdentity, bool, error) {
+	var query struct {
+		Organization struct {
+			SamlIdentityProvider struct {
+				ID                 githubv4.String
+				ExternalIdentities struct {
+					PageInfo PageInfo
+					Nodes    []struct {
+						GUID         githubv4.String `graphql:"guid"`
+						SamlIdentity struct {
+							NameID githubv4.String `graphql:"nameId"`
+							Emails []struct {
+								Value githubv4.String
+							}
+						}
+						ScimIdentity struct {
+							Username githubv4.String
+							Emails   []struct {
+								Value githubv4.String
+							}
+						}
+						User struct {
+							Login githubv4.String
+						}
+					}
+				} `graphql:"externalIdentities(first: 100, after: $cursor)"`
+			}
+		} `graphql:"organization(login: $org)"`
+	}
+	var identities []*ExternalIdentity
+	err := s.Query(&query).Str("org", org).Cursor("cursor").RunPaginated(func() PageInfo {
+		for _, node := range query.Organization.SamlIdentityProvider.ExternalIdentities.Nodes {
+			identity := &ExternalIdentity{
+				GUID:         string(node.GUID),
+				NameID:       string(node.SamlIdentity.NameID),
+				ScimUsername: string(node.ScimIdentity.Username),
+				Login:        string(node.User.Login),
+			}
+			for _, email := range node.SamlIdentity.Emails {
+				identity.Emails = append(identity.Emails, string(email.Value))
+			}
+			for _, email := range node.ScimIdentity.Emails {
+				identity.Emails = append(identity.Emails, string(email.Value))
+			}
+			identities = append(identities, identity)
+		}
+		return query.Organization.SamlIdentityProvider.ExternalIdentities.PageInfo
+	})
+	return identities, query.Organization.SamlIdentityProvider.ID != "", err
+}
+
+// LoadMemberExternalIdentities attaches the linked external identity to each of the given members and returns all
+// identities that are not linked to any of them.
+func (s *GithubClient) LoadMemberExternalIdentities(org string, members ...*Member) ([]*ExternalIdentity, error) {
+	identities, _, err := s.GetExternalIdentities(org)
+	if err != nil {
+		return nil, err
+	}
+	return linkExternalIdentities(identities, members), nil
+}
+
+func linkExternalIdentities(identities []*ExternalIdentity, members []*Member) []*ExternalIdentity {
+	byLogin := make(map[string]*Member)
+	for _, m := range members {
+		byLogin[m.Login] = m
+	}
+	var unlinked []*ExternalIdentity
+	for _, identity := range identities {
+		if m, ok := byLogin[identity.Login]; ok && identity.Login != "" {
+			m.ExternalIdentity = identity
+		} else {
+			unlinked = append(unlinked, identity)
+		}
+	}
+	return unlinked
+}
@@ -0,0 +1,148 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+// ManifestEntry records a lifecycle operation carried out on a repository, along with what is needed to reverse it.
+type ManifestEntry struct {
+	Operation string    `json:"operation,omitempty"`
+	Time      time.Time `json:"time,omitempty"`
+	// Repository is the full name of the repository before the operation, Result its full name afterwards.
+	Repository string `json:"repository,omitempty"`
+	Result     string `json:"result,omitempty"`
+	// Reverse is the operation that undoes this one, it is empty if the operation can not be reversed.
+	Reverse string `json:"reverse,omitempty"`
+	// Teams holds the permissions teams had on the repository before it was transferred.
+	Teams map[string]string `json:"teams,omitempty"`
+	// Snapshot holds the settings of a deleted repository.
+	Snapshot *Repository `json:"snapshot,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+func newManifestEntry(operation string, repository *Repository, reverse string) *ManifestEntry {
+	name := repository.Owner + "/" + repository.Name
+	return &ManifestEntry{
+		Operation:  operation,
+		Time:       time.Now(),
+		Repository: name,
+		Result:     name,
+		Reverse:    reverse,
+	}
+}
+
+func (s *ManifestEntry) fail(err error) (*ManifestEntry, error) {
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return s, err
+}
+
+// AppendManifest appends the entries to the manifest in the given file, which is created if it does not exist.
+func AppendManifest(filename string, entries ...*ManifestEntry) error {
+	var manifest []*ManifestEntry
+	if content, err := ioutil.ReadFile(filename); err == nil {
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("reading manifest %s: %w", filename, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	content, err := json.MarshalIndent(append(manifest, entries...), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, content, 0644)
+}
+
+func (s *GithubClient) ArchiveRepository(repository *Repository) (*ManifestEntry, error) {
+	entry := newManifestEntry("archive", repository, "unarchive")
+	return entry.fail(s.setRepositoryArchived(repository, true))
+}
+
+func (s *GithubClient) UnarchiveRepository(repository *Repository) (*ManifestEntry, error) {
+	entry := newManifestEntry("unarchive", repository, "archive")
+	return entry.fail(s.setRepositoryArchived(repository, false))
+}
+
+func (s *GithubClient) setRepositoryArchived(repository *Repository, archived bool) error {
+	_, _, err := s.v3Client.Repositories.Edit(context.Background(), repository.Owner, repository.Name, &gh3.Repository{
+		Archived: &archived,
+	})
+	if err == nil {
+		repository.Archived = archived
+	}
+	return err
+}
+
+func (s *GithubClient) RenameRepository(repository *Repository, name string) (*ManifestEntry, error) {
+	entry := newManifestEntry("rename", repository, "rename")
+	if _, _, err := s.v3Client.Repositories.Edit(context.Background(), repository.Owner, repository.Name, &gh3.Repository{
+		Name: &name,
+	}); err != nil {
+		return entry.fail(err)
+	}
+	repository.Name = name
+	entry.Result = repository.Owner + "/" + name
+	return entry, nil
+}
+
+// TransferRepository transfers the repository to another owner. Teams of the current owner that have access to the
+// repository are mapped to teams of the new owner by slug using teamMapping. Github completes transfers
+// asynchronously, so mapped teams are given access as part of the transfer request, which grants them the default
+// permission of the new owner. The permissions teams had before are kept in the manifest entry, teams without a
+// mapping lose their access.
+func (s *GithubClient) TransferRepository(repository *Repository, newOwner string, teamMapping map[string]string) (*ManifestEntry, error) {
+	entry := newManifestEntry("transfer", repository, "transfer")
+	ctx := context.Background()
+	entry.Teams = make(map[string]string)
+	if err := s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		teams, resp, err := s.v3Client.Repositories.ListTeams(ctx, repository.Owner, repository.Name, &lo)
+		for _, t := range teams {
+			entry.Teams[t.GetSlug()] = t.GetPermission()
+		}
+		return resp, err
+	}); err != nil {
+		return entry.fail(err)
+	}
+	var teamIDs []int64
+	for source, target := range teamMapping {
+		if _, ok := entry.Teams[source]; !ok {
+			return entry.fail(fmt.Errorf("team %s has no access to %s", source, entry.Repository))
+		}
+		team, _, err := s.v3Client.Teams.GetTeamBySlug(ctx, newOwner, target)
+		if err != nil {
+			return entry.fail(fmt.Errorf("team %s of %s: %w", target, newOwner, err))
+		}
+		teamIDs = append(teamIDs, team.GetID())
+	}
+	if _, _, err := s.v3Client.Repositories.Transfer(ctx, repository.Owner, repository.Name, gh3.TransferRequest{
+		NewOwner: newOwner,
+		TeamID:   teamIDs,
+	}); err != nil {
+		if _, accepted := err.(*gh3.AcceptedError); !accepted {
+			return entry.fail(err)
+		}
+	}
+	repository.Owner = newOwner
+	entry.Result = newOwner + "/" + repository.Name
+	return entry, nil
+}
+
+// DeleteRepository deletes the repository. The manifest entry keeps a snapshot of the repository settings, but the
+// deletion itself can not be reversed.
+func (s *GithubClient) DeleteRepository(repository *Repository) (*ManifestEntry, error) {
+	entry := newManifestEntry("delete", repository, "")
+	snapshot := *repository
+	entry.Snapshot = &snapshot
+	entry.Result = ""
+	_, err := s.v3Client.Repositories.Delete(context.Background(), repository.Owner, repository.Name)
+	return entry.fail(err)
+}
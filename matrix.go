@@ -0,0 +1,118 @@
+package github
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// AccessMatrix holds the permission of every team (rows) on every repository (columns). Permissions inherited from a
+// parent team are annotated with the team they are inherited from.
+type AccessMatrix struct {
+	Teams        []string   `json:"teams"`
+	Repositories []string   `json:"repositories"`
+	Permissions  [][]string `json:"permissions"`
+}
+
+func (s *GithubClient) TeamAccessMatrix(org string, filters ...RepositoryFilter) (*AccessMatrix, error) {
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.LoadTeamRepositories(org, teams...); err != nil {
+		return nil, err
+	}
+	if err := s.restrictTeamRepositories(org, teams, filters...); err != nil {
+		return nil, err
+	}
+	return BuildAccessMatrix(BuildTeamTree(teams)), nil
+}
+
+func BuildAccessMatrix(roots []*TeamNode) *AccessMatrix {
+	matrix := &AccessMatrix{}
+	rows := make(map[string]map[string]string)
+	columns := make(map[string]bool)
+	for _, root := range roots {
+		root.Walk(func(team *TeamNode, depth int) {
+			row := make(map[string]string)
+			for _, r := range team.Repositories {
+				row[r.Name] = strings.ToLower(r.Permission)
+				columns[r.Name] = true
+			}
+			for _, r := range team.InheritedRepositories {
+				row[r.Name] = fmt.Sprintf("%s (via %s)", strings.ToLower(r.Permission), r.Via)
+				columns[r.Name] = true
+			}
+			rows[team.Slug] = row
+			matrix.Teams = append(matrix.Teams, team.Slug)
+		})
+	}
+	for name := range columns {
+		matrix.Repositories = append(matrix.Repositories, name)
+	}
+	sort.Strings(matrix.Teams)
+	sort.Strings(matrix.Repositories)
+	for _, team := range matrix.Teams {
+		var permissions []string
+		for _, repository := range matrix.Repositories {
+			permissions = append(permissions, rows[team][repository])
+		}
+		matrix.Permissions = append(matrix.Permissions, permissions)
+	}
+	return matrix
+}
+
+func (s *AccessMatrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"team"}, s.Repositories...)); err != nil {
+		return err
+	}
+	for i, team := range s.Teams {
+		if err := writer.Write(append([]string{team}, s.Permissions[i]...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *AccessMatrix) WriteMarkdown(w io.Writer) error {
+	escape := strings.NewReplacer("|", `\|`).Replace
+	var sb strings.Builder
+	sb.WriteString("| team |")
+	for _, repository := range s.Repositories {
+		sb.WriteString(" " + escape(repository) + " |")
+	}
+	sb.WriteString("\n|---|" + strings.Repeat("---|", len(s.Repositories)) + "\n")
+	for i, team := range s.Teams {
+		sb.WriteString("| " + escape(team) + " |")
+		for _, permission := range s.Permissions[i] {
+			sb.WriteString(" " + escape(permission) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (s *AccessMatrix) WriteHTML(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("<table>\n<thead>\n<tr><th>team</th>")
+	for _, repository := range s.Repositories {
+		sb.WriteString("<th>" + html.EscapeString(repository) + "</th>")
+	}
+	sb.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for i, team := range s.Teams {
+		sb.WriteString("<tr><th>" + html.EscapeString(team) + "</th>")
+		for _, permission := range s.Permissions[i] {
+			sb.WriteString("<td>" + html.EscapeString(permission) + "</td>")
+		}
+		sb.WriteString("</tr>\n")
+	}
+	sb.WriteString("</tbody>\n</table>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
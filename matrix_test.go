@@ -0,0 +1,51 @@
+package github_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("AccessMatrix", func() {
+	matrix := func() *AccessMatrix {
+		return BuildAccessMatrix(BuildTeamTree([]*Team{
+			{ID: "1", Slug: "platform", Repositories: []*TeamRepository{{Owner: "org", Name: "infra", Permission: "MAINTAIN"}}},
+			{ID: "2", Slug: "backend", Parent: "1", Repositories: []*TeamRepository{{Owner: "org", Name: "api|v2", Permission: "WRITE"}}},
+			{ID: "3", Slug: "auditors"},
+		}))
+	}
+
+	It("lists the direct and inherited permission of every team on every repository", func() {
+		Expect(*matrix()).To(Equal(AccessMatrix{
+			Teams:        []string{"auditors", "backend", "platform"},
+			Repositories: []string{"api|v2", "infra"},
+			Permissions: [][]string{
+				{"", ""},
+				{"write", "maintain (via platform)"},
+				{"", "maintain"},
+			},
+		}))
+	})
+
+	It("writes CSV", func() {
+		var out bytes.Buffer
+		Expect(matrix().WriteCSV(&out)).To(Succeed())
+		Expect(out.String()).To(Equal("team,api|v2,infra\n" +
+			"auditors,,\n" +
+			"backend,write,maintain (via platform)\n" +
+			"platform,,maintain\n"))
+	})
+
+	It("writes Markdown", func() {
+		var out bytes.Buffer
+		Expect(matrix().WriteMarkdown(&out)).To(Succeed())
+		Expect(out.String()).To(Equal("| team | api\\|v2 | infra |\n" +
+			"|---|---|---|\n" +
+			"| auditors |  |  |\n" +
+			"| backend | write | maintain (via platform) |\n" +
+			"| platform |  | maintain |\n"))
+	})
+})
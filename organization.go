@@ -1,23 +1,145 @@
 package github
 
 import (
+	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	gh3 "github.com/google/go-github/v32/github"
 	"github.com/shurcooL/githubv4"
 )
 
+const (
+	RoleAdmin               = "ADMIN"
+	RoleMember              = "MEMBER"
+	RoleOutsideCollaborator = "OUTSIDE_COLLABORATOR"
+)
+
 type Organization struct {
-	Name string
+	Login                               string `json:"login,omitempty"`
+	Name                                string `json:"name,omitempty"`
+	BillingEmail                        string `json:"billing_email,omitempty"`
+	PlanName                            string `json:"plan_name,omitempty"`
+	PlanSeats                           int    `json:"plan_seats"`
+	PlanFilledSeats                     int    `json:"plan_filled_seats"`
+	DefaultRepositoryPermission         string `json:"default_repository_permission,omitempty"`
+	MembersCanCreatePublicRepositories  bool   `json:"members_can_create_public_repositories"`
+	MembersCanCreatePrivateRepositories bool   `json:"members_can_create_private_repositories"`
+	MembersCanForkPrivateRepositories   bool   `json:"members_can_fork_private_repositories"`
+	TwoFactorRequirementEnabled         bool   `json:"two_factor_requirement_enabled"`
+	WebCommitSignoffRequired            bool   `json:"web_commit_signoff_required"`
+}
+
+func (s *GithubClient) GetOrganization(org string) (*Organization, error) {
+	// go-github does not know about all settings yet, so the response is decoded into a custom struct.
+	var o struct {
+		Login        string `json:"login"`
+		Name         string `json:"name"`
+		BillingEmail string `json:"billing_email"`
+		Plan         struct {
+			Name        string `json:"name"`
+			Seats       int    `json:"seats"`
+			FilledSeats int    `json:"filled_seats"`
+		} `json:"plan"`
+		DefaultRepositoryPermission         string `json:"default_repository_permission"`
+		MembersCanCreatePublicRepositories  bool   `json:"members_can_create_public_repositories"`
+		MembersCanCreatePrivateRepositories bool   `json:"members_can_create_private_repositories"`
+		MembersCanForkPrivateRepositories   bool   `json:"members_can_fork_private_repositories"`
+		TwoFactorRequirementEnabled         bool   `json:"two_factor_requirement_enabled"`
+		WebCommitSignoffRequired            bool   `json:"web_commit_signoff_required"`
+	}
+	req, err := s.v3Client.NewRequest("GET", fmt.Sprintf("orgs/%v", org), nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.v3Client.Do(context.Background(), req, &o); err != nil {
+		return nil, err
+	}
+	return &Organization{
+		Login:                               o.Login,
+		Name:                                o.Name,
+		BillingEmail:                        o.BillingEmail,
+		PlanName:                            o.Plan.Name,
+		PlanSeats:                           o.Plan.Seats,
+		PlanFilledSeats:                     o.Plan.FilledSeats,
+		DefaultRepositoryPermission:         o.DefaultRepositoryPermission,
+		MembersCanCreatePublicRepositories:  o.MembersCanCreatePublicRepositories,
+		MembersCanCreatePrivateRepositories: o.MembersCanCreatePrivateRepositories,
+		MembersCanForkPrivateRepositories:   o.MembersCanForkPrivateRepositories,
+		TwoFactorRequirementEnabled:         o.TwoFactorRequirementEnabled,
+		WebCommitSignoffRequired:            o.WebCommitSignoffRequired,
+	}, nil
+}
+
+type OrganizationBaseline struct {
+	DefaultRepositoryPermission         *string `json:"default_repository_permission,omitempty"`
+	MembersCanCreatePublicRepositories  *bool   `json:"members_can_create_public_repositories,omitempty"`
+	MembersCanCreatePrivateRepositories *bool   `json:"members_can_create_private_repositories,omitempty"`
+	MembersCanForkPrivateRepositories   *bool   `json:"members_can_fork_private_repositories,omitempty"`
+	TwoFactorRequirementEnabled         *bool   `json:"two_factor_requirement_enabled,omitempty"`
+	WebCommitSignoffRequired            *bool   `json:"web_commit_signoff_required,omitempty"`
+	BillingEmail                        *string `json:"billing_email,omitempty"`
+	MaxFilledSeats                      *int    `json:"max_filled_seats,omitempty"`
+}
+
+type SettingDeviation struct {
+	Setting  string      `json:"setting,omitempty"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+}
+
+// Check compares the settings of the given organization with the baseline. Settings that are not part of the
+// baseline are ignored.
+func (s OrganizationBaseline) Check(o *Organization) []SettingDeviation {
+	var deviations []SettingDeviation
+	check := func(setting string, expected, actual interface{}, matches bool) {
+		if !matches {
+			deviations = append(deviations, SettingDeviation{
+				Setting:  setting,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+	}
+	if s.DefaultRepositoryPermission != nil {
+		check("default_repository_permission", *s.DefaultRepositoryPermission, o.DefaultRepositoryPermission, *s.DefaultRepositoryPermission == o.DefaultRepositoryPermission)
+	}
+	if s.MembersCanCreatePublicRepositories != nil {
+		check("members_can_create_public_repositories", *s.MembersCanCreatePublicRepositories, o.MembersCanCreatePublicRepositories, *s.MembersCanCreatePublicRepositories == o.MembersCanCreatePublicRepositories)
+	}
+	if s.MembersCanCreatePrivateRepositories != nil {
+		check("members_can_create_private_repositories", *s.MembersCanCreatePrivateRepositories, o.MembersCanCreatePrivateRepositories, *s.MembersCanCreatePrivateRepositories == o.MembersCanCreatePrivateRepositories)
+	}
+	if s.MembersCanForkPrivateRepositories != nil {
+		check("members_can_fork_private_repositories", *s.MembersCanForkPrivateRepositories, o.MembersCanForkPrivateRepositories, *s.MembersCanForkPrivateRepositories == o.MembersCanForkPrivateRepositories)
+	}
+	if s.TwoFactorRequirementEnabled != nil {
+		check("two_factor_requirement_enabled", *s.TwoFactorRequirementEnabled, o.TwoFactorRequirementEnabled, *s.TwoFactorRequirementEnabled == o.TwoFactorRequirementEnabled)
+	}
+	if s.WebCommitSignoffRequired != nil {
+		check("web_commit_signoff_required", *s.WebCommitSignoffRequired, o.WebCommitSignoffRequired, *s.WebCommitSignoffRequired == o.WebCommitSignoffRequired)
+	}
+	if s.BillingEmail != nil {
+		check("billing_email", *s.BillingEmail, o.BillingEmail, *s.BillingEmail == o.BillingEmail)
+	}
+	if s.MaxFilledSeats != nil {
+		check("plan_filled_seats", *s.MaxFilledSeats, o.PlanFilledSeats, o.PlanFilledSeats <= *s.MaxFilledSeats)
+	}
+	return deviations
 }
 
 type Member struct {
-	Login               string    `json:"login,omitempty"`
-	Name                string    `json:"name,omitempty"`
-	Email               string    `json:"email,omitempty"`
-	HasTwoFactorEnabled bool      `json:"has_two_factor_enabled,omitempty"`
-	Role                string    `json:"role,omitempty"`
-	CreatedAt           time.Time `json:"created_at,omitempty"`
-	Pending             bool      `json:"pending"`
+	Login               string            `json:"login,omitempty"`
+	Name                string            `json:"name,omitempty"`
+	Email               string            `json:"email,omitempty"`
+	HasTwoFactorEnabled bool              `json:"has_two_factor_enabled,omitempty"`
+	Role                string            `json:"role,omitempty"`
+	CreatedAt           time.Time         `json:"created_at,omitempty"`
+	Pending             bool              `json:"pending"`
+	ExternalIdentity    *ExternalIdentity `json:"external_identity,omitempty"`
+	PublicKeys          []PublicKey       `json:"public_keys,omitempty"`
+	GPGKeys             []GPGKey          `json:"gpg_keys,omitempty"`
 }
 
 type MemberOption func()
@@ -41,6 +163,19 @@ func (s *GithubClient) GetMembers(org string, opts ...MemberOption) ([]*Member,
 	return members, nil
 }
 
+func (s *GithubClient) LoadMemberKeys(members ...*Member) error {
+	for _, member := range members {
+		var err error
+		if member.PublicKeys, err = s.ListPublicKeys(member.Login); err != nil {
+			return err
+		}
+		if member.GPGKeys, err = s.ListGPGKeys(member.Login); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *GithubClient) getNonPendingMembers(org string) ([]*Member, error) {
 	var query struct {
 		Organization struct {
@@ -109,11 +244,125 @@ func (s *GithubClient) getPendingMembers(org string) ([]*Member, error) {
 	})
 }
 
+func (s *GithubClient) GetOutsideCollaborators(org string) ([]*Member, error) {
+	withoutTwoFactor := make(map[string]bool)
+	err := s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		users, resp, err := s.v3Client.Organizations.ListOutsideCollaborators(context.Background(), org, &gh3.ListOutsideCollaboratorsOptions{
+			Filter:      "2fa_disabled",
+			ListOptions: lo,
+		})
+		for _, u := range users {
+			withoutTwoFactor[u.GetLogin()] = true
+		}
+		return resp, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var collaborators []*Member
+	return collaborators, s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		users, resp, err := s.v3Client.Organizations.ListOutsideCollaborators(context.Background(), org, &gh3.ListOutsideCollaboratorsOptions{
+			Filter:      "all",
+			ListOptions: lo,
+		})
+		for _, u := range users {
+			collaborators = append(collaborators, &Member{
+				Login:               u.GetLogin(),
+				Name:                u.GetName(),
+				Email:               u.GetEmail(),
+				HasTwoFactorEnabled: !withoutTwoFactor[u.GetLogin()],
+				Role:                RoleOutsideCollaborator,
+				CreatedAt:           u.GetCreatedAt().Time,
+			})
+		}
+		return resp, err
+	})
+}
+
+func (s *GithubClient) RemoveMember(org string, login string) error {
+	_, err := s.v3Client.Organizations.RemoveMember(context.Background(), org, login)
+	return err
+}
+
+func (s *GithubClient) RemoveOutsideCollaborator(org string, login string) error {
+	_, err := s.v3Client.Organizations.RemoveOutsideCollaborator(context.Background(), org, login)
+	return err
+}
+
+type BlockedUser struct {
+	Login string `json:"login,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+func (s *GithubClient) GetBlockedUsers(org string) ([]*BlockedUser, error) {
+	var blocked []*BlockedUser
+	return blocked, s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		users, resp, err := s.v3Client.Organizations.ListBlockedUsers(context.Background(), org, &lo)
+		for _, u := range users {
+			blocked = append(blocked, &BlockedUser{
+				Login: u.GetLogin(),
+				Name:  u.GetName(),
+			})
+		}
+		return resp, err
+	})
+}
+
+func (s *GithubClient) BlockUser(org string, login string) error {
+	_, err := s.v3Client.Organizations.BlockUser(context.Background(), org, login)
+	return err
+}
+
+func (s *GithubClient) UnblockUser(org string, login string) error {
+	_, err := s.v3Client.Organizations.UnblockUser(context.Background(), org, login)
+	return err
+}
+
+type InteractionLimit struct {
+	Limit     string    `json:"limit,omitempty"`
+	Origin    string    `json:"origin,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+func fromGh3InteractionRestriction(r *gh3.InteractionRestriction) *InteractionLimit {
+	if r.GetLimit() == "" {
+		return nil
+	}
+	return &InteractionLimit{
+		Limit:     r.GetLimit(),
+		Origin:    r.GetOrigin(),
+		ExpiresAt: r.GetExpiresAt().Time,
+	}
+}
+
+// GetInteractionLimit returns the interaction limit currently active on the organization or nil if there is none.
+func (s *GithubClient) GetInteractionLimit(org string) (*InteractionLimit, error) {
+	restriction, _, err := s.v3Client.Interactions.GetRestrictionsForOrg(context.Background(), org)
+	if err != nil {
+		return nil, err
+	}
+	return fromGh3InteractionRestriction(restriction), nil
+}
+
+// SetInteractionLimit limits interactions on all public repositories of the organization. Valid limits are
+// "existing_users", "contributors_only" and "collaborators_only".
+func (s *GithubClient) SetInteractionLimit(org string, limit string) error {
+	_, _, err := s.v3Client.Interactions.UpdateRestrictionsForOrg(context.Background(), org, limit)
+	return err
+}
+
+func (s *GithubClient) RemoveInteractionLimit(org string) error {
+	_, err := s.v3Client.Interactions.RemoveRestrictionsFromOrg(context.Background(), org)
+	return err
+}
+
 type Team struct {
 	ID              string            `json:"id,omitempty"`
 	Name            string            `json:"name,omitempty"`
 	Slug            string            `json:"slug,omitempty"`
 	CombinedSlug    string            `json:"combined_slug,omitempty"`
+	Description     string            `json:"description,omitempty"`
+	Privacy         string            `json:"privacy,omitempty"`
 	Parent          string            `json:"parent,omitempty"`
 	MemberCount     int               `json:"member_count,omitempty"`
 	Members         []*TeamMember     `json:"members,omitempty"`
@@ -132,6 +381,8 @@ func (s *GithubClient) GetTeams(org string) ([]*Team, error) {
 					Name         githubv4.String
 					Slug         githubv4.String
 					CombinedSlug githubv4.String
+					Description  githubv4.String
+					Privacy      githubv4.String
 					ParentTeam   struct {
 						ID githubv4.String
 					}
@@ -156,6 +407,8 @@ func (s *GithubClient) GetTeams(org string) ([]*Team, error) {
 				Name:            string(node.Name),
 				Slug:            string(node.Slug),
 				CombinedSlug:    string(node.CombinedSlug),
+				Description:     string(node.Description),
+				Privacy:         string(node.Privacy),
 				Parent:          string(node.ParentTeam.ID),
 				MemberCount:     int(node.Members.TotalCount),
 				RepositoryCount: int(node.Repositories.TotalCount),
@@ -167,6 +420,11 @@ func (s *GithubClient) GetTeams(org string) ([]*Team, error) {
 	})
 }
 
+const (
+	TeamRoleMaintainer = "MAINTAINER"
+	TeamRoleMember     = "MEMBER"
+)
+
 type TeamMember struct {
 	Login string `json:"login,omitempty"`
 	Role  string `json:"role,omitempty"`
@@ -174,14 +432,24 @@ type TeamMember struct {
 
 func (s *GithubClient) LoadTeamMembers(org string, teams ...*Team) error {
 	for _, team := range teams {
-		if err := s.loadTeamMembers(org, team); err != nil {
+		if err := s.loadTeamMembers(org, team, githubv4.TeamMembershipTypeAll); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadImmediateTeamMembers loads only the direct members of the teams, leaving out members of their child teams.
+func (s *GithubClient) LoadImmediateTeamMembers(org string, teams ...*Team) error {
+	for _, team := range teams {
+		if err := s.loadTeamMembers(org, team, githubv4.TeamMembershipTypeImmediate); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (s *GithubClient) loadTeamMembers(org string, team *Team) error {
+func (s *GithubClient) loadTeamMembers(org string, team *Team, membership githubv4.TeamMembershipType) error {
 	var query struct {
 		Organization struct {
 			Team struct {
@@ -193,11 +461,11 @@ func (s *GithubClient) loadTeamMembers(org string, team *Team) error {
 						}
 						Role githubv4.String
 					}
-				} `graphql:"members(first: 100, after: $cursor)"`
+				} `graphql:"members(first: 100, after: $cursor, membership: $membership)"`
 			} `graphql:"team(slug: $slug)"`
 		} `graphql:"organization(login: $org)"`
 	}
-	return s.Query(&query).Str("org", org).Str("slug", team.Slug).Cursor("cursor").RunPaginated(func() PageInfo {
+	return s.Query(&query).Str("org", org).Str("slug", team.Slug).Var("membership", membership).Cursor("cursor").RunPaginated(func() PageInfo {
 		for _, m := range query.Organization.Team.Members.Edges {
 			teamMember := &TeamMember{
 				Login: string(m.Node.Login),
@@ -256,91 +524,154 @@ func (s *GithubClient) loadTeamRepositories(org string, team *Team) error {
 	})
 }
 
+// restrictTeamRepositories drops all loaded team repositories that do not match the filters.
+func (s *GithubClient) restrictTeamRepositories(org string, teams []*Team, filters ...RepositoryFilter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return err
+	}
+	selected := make(map[string]bool)
+	for _, r := range FilterRepositories(repositories, filters...) {
+		selected[r.Owner+"/"+r.Name] = true
+	}
+	for _, team := range teams {
+		var restricted []*TeamRepository
+		for _, r := range team.Repositories {
+			if selected[r.Owner+"/"+r.Name] {
+				restricted = append(restricted, r)
+			}
+		}
+		team.Repositories = restricted
+	}
+	return nil
+}
+
+type TeamSettings struct {
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Privacy      string `json:"privacy,omitempty"`
+	ParentSlug   string `json:"parent_slug,omitempty"`
+	RemoveParent bool   `json:"remove_parent,omitempty"`
+}
+
+func (s *GithubClient) newTeam(org string, name string, settings TeamSettings) (gh3.NewTeam, error) {
+	team := gh3.NewTeam{
+		Name:        name,
+		Description: boxString(settings.Description),
+		Privacy:     boxString(strings.ToLower(settings.Privacy)),
+	}
+	if team.Privacy != nil && *team.Privacy == "visible" {
+		team.Privacy = boxString("closed")
+	}
+	if settings.ParentSlug != "" {
+		parent, _, err := s.v3Client.Teams.GetTeamBySlug(context.Background(), org, settings.ParentSlug)
+		if err != nil {
+			return team, err
+		}
+		team.ParentTeamID = parent.ID
+	}
+	return team, nil
+}
+
+func fromGh3Team(t *gh3.Team) *Team {
+	privacy := strings.ToUpper(t.GetPrivacy())
+	if privacy == "CLOSED" {
+		privacy = "VISIBLE"
+	}
+	return &Team{
+		ID:              t.GetNodeID(),
+		Name:            t.GetName(),
+		Slug:            t.GetSlug(),
+		Description:     t.GetDescription(),
+		Privacy:         privacy,
+		Parent:          t.GetParent().GetNodeID(),
+		MemberCount:     t.GetMembersCount(),
+		RepositoryCount: t.GetReposCount(),
+	}
+}
+
+func (s *GithubClient) CreateTeam(org string, settings TeamSettings) (*Team, error) {
+	newTeam, err := s.newTeam(org, settings.Name, settings)
+	if err != nil {
+		return nil, err
+	}
+	team, _, err := s.v3Client.Teams.CreateTeam(context.Background(), org, newTeam)
+	if err != nil {
+		return nil, err
+	}
+	return fromGh3Team(team), nil
+}
+
+// UpdateTeam changes the settings of the team with the given slug. Empty settings are left unchanged.
+func (s *GithubClient) UpdateTeam(org string, slug string, settings TeamSettings) (*Team, error) {
+	name := settings.Name
+	if name == "" {
+		current, _, err := s.v3Client.Teams.GetTeamBySlug(context.Background(), org, slug)
+		if err != nil {
+			return nil, err
+		}
+		name = current.GetName()
+	}
+	newTeam, err := s.newTeam(org, name, settings)
+	if err != nil {
+		return nil, err
+	}
+	team, _, err := s.v3Client.Teams.EditTeamBySlug(context.Background(), org, slug, newTeam, settings.RemoveParent)
+	if err != nil {
+		return nil, err
+	}
+	return fromGh3Team(team), nil
+}
+
+func (s *GithubClient) DeleteTeam(org string, slug string) error {
+	_, err := s.v3Client.Teams.DeleteTeamBySlug(context.Background(), org, slug)
+	return err
+}
+
+// AddTeamMember adds the user to the team or changes the role of an existing member. Valid roles are "member" and
+// "maintainer".
+func (s *GithubClient) AddTeamMember(org string, slug string, login string, role string) error {
+	_, _, err := s.v3Client.Teams.AddTeamMembershipBySlug(context.Background(), org, slug, login, &gh3.TeamAddTeamMembershipOptions{
+		Role: strings.ToLower(role),
+	})
+	return err
+}
+
+func (s *GithubClient) RemoveTeamMember(org string, slug string, login string) error {
+	_, err := s.v3Client.Teams.RemoveTeamMembershipBySlug(context.Background(), org, slug, login)
+	return err
+}
+
+// GrantTeamRepository grants the team the permission on the repository or changes an existing permission. Valid
+// permissions are "pull", "triage", "push", "maintain" and "admin".
+func (s *GithubClient) GrantTeamRepository(org string, slug string, owner string, repository string, permission string) error {
+	_, err := s.v3Client.Teams.AddTeamRepoBySlug(context.Background(), org, slug, owner, repository, &gh3.TeamAddTeamRepoOptions{
+		Permission: restPermission(permission),
+	})
+	return err
+}
+
+func (s *GithubClient) RevokeTeamRepository(org string, slug string, owner string, repository string) error {
+	_, err := s.v3Client.Teams.RemoveTeamRepoBySlug(context.Background(), org, slug, owner, repository)
+	return err
+}
+
 func (s *GithubClient) GetOrganizationRepositories(org string) ([]*Repository, error) {
 	var query struct {
 		Organization struct {
 			Repositories struct {
 				PageInfo PageInfo
-				Nodes    []struct {
-					Owner struct {
-						Login githubv4.String
-					}
-					Name             githubv4.String
-					CreatedAt        githubv4.DateTime
-					DefaultBranchRef struct {
-						Name githubv4.String
-					}
-					Description          githubv4.String
-					DescriptionHTML      githubv4.String `graphql:"descriptionHTML"`
-					ShortDescriptionHTML githubv4.String `graphql:"shortDescriptionHTML"`
-					HasIssuesEnabled     githubv4.Boolean
-					HasProjectsEnabled   githubv4.Boolean
-					HasWikiEnabled       githubv4.Boolean
-					HomepageURL          githubv4.String
-					URL                  githubv4.String
-					SshURL               githubv4.String
-					IsArchived           githubv4.Boolean
-					IsDisabled           githubv4.Boolean
-					IsEmpty              githubv4.Boolean
-					IsFork               githubv4.Boolean
-					IsLocked             githubv4.Boolean
-					IsMirror             githubv4.Boolean
-					IsPrivate            githubv4.Boolean
-					IsTemplate           githubv4.Boolean
-					LicenseInfo          struct {
-						Name githubv4.String
-					}
-					Labels struct {
-						TotalCount githubv4.Int
-					}
-					Languages struct {
-						TotalCount githubv4.Int
-					}
-					RebaseMergeAllowed  githubv4.Boolean
-					MergeCommitAllowed  githubv4.Boolean
-					SquashMergeAllowed  githubv4.Boolean
-					DeleteBranchOnMerge githubv4.Boolean
-					DiskUsage           githubv4.Int
-					PushedAt            githubv4.DateTime
-					UpdatedAt           githubv4.DateTime
-					PrimaryLanguage     struct {
-						Name githubv4.String
-					}
-				}
+				Nodes    []repositoryNode
 			} `graphql:"repositories(first: 100, after: $cursor)"`
 		} `graphql:"organization(login: $org)"`
 	}
 	var repositories []*Repository
 	return repositories, s.Query(&query).Str("org", org).Cursor("cursor").RunPaginated(func() PageInfo {
 		for _, node := range query.Organization.Repositories.Nodes {
-			r := &Repository{
-				Owner:               string(node.Owner.Login),
-				Name:                string(node.Name),
-				Private:             bool(node.IsPrivate),
-				Description:         string(node.Description),
-				Homepage:            string(node.HomepageURL),
-				URL:                 string(node.URL),
-				Ssh:                 string(node.SshURL),
-				DefaultBranch:       string(node.DefaultBranchRef.Name),
-				Archived:            bool(node.IsArchived),
-				Disabled:            bool(node.IsDisabled),
-				Template:            bool(node.IsTemplate),
-				IssuesEnabled:       bool(node.HasIssuesEnabled),
-				ProjectsEnabled:     bool(node.HasProjectsEnabled),
-				WikiEnabled:         bool(node.HasWikiEnabled),
-				AllowRebaseMerge:    bool(node.RebaseMergeAllowed),
-				AllowSquashMerge:    bool(node.SquashMergeAllowed),
-				AllowMergeCommit:    bool(node.MergeCommitAllowed),
-				DeleteBranchOnMerge: bool(node.DeleteBranchOnMerge),
-				LicenseName:         string(node.LicenseInfo.Name),
-				Topics:              nil,
-				DiskUsage:           int(node.DiskUsage),
-				PushedAt:            node.PushedAt.Time,
-				UpdatedAt:           node.UpdatedAt.Time,
-				Age:                 int(time.Now().Sub(node.PushedAt.Time).Seconds()),
-				PrimaryLanguage:     string(node.PrimaryLanguage.Name),
-			}
-			repositories = append(repositories, r)
+			repositories = append(repositories, node.toRepository())
 		}
 		return query.Organization.Repositories.PageInfo
 	})
@@ -0,0 +1,48 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("OrganizationBaseline", func() {
+	organization := &Organization{
+		Login:                              "org",
+		BillingEmail:                       "billing@example.com",
+		PlanFilledSeats:                    42,
+		DefaultRepositoryPermission:        "read",
+		MembersCanCreatePublicRepositories: true,
+		TwoFactorRequirementEnabled:        true,
+	}
+
+	DescribeTable("reports deviating settings",
+		func(baseline OrganizationBaseline, expected []SettingDeviation) {
+			Expect(baseline.Check(organization)).To(Equal(expected))
+		},
+		Entry("none for an empty baseline", OrganizationBaseline{}, nil),
+		Entry("none for matching settings", OrganizationBaseline{
+			DefaultRepositoryPermission:       str("read"),
+			TwoFactorRequirementEnabled:       boolean(true),
+			MembersCanForkPrivateRepositories: boolean(false),
+			BillingEmail:                      str("billing@example.com"),
+			MaxFilledSeats:                    number(42),
+		}, nil),
+		Entry("deviating settings", OrganizationBaseline{
+			DefaultRepositoryPermission:        str("none"),
+			MembersCanCreatePublicRepositories: boolean(false),
+			WebCommitSignoffRequired:           boolean(true),
+			BillingEmail:                       str("it@example.com"),
+		}, []SettingDeviation{
+			{Setting: "default_repository_permission", Expected: "none", Actual: "read"},
+			{Setting: "members_can_create_public_repositories", Expected: false, Actual: true},
+			{Setting: "web_commit_signoff_required", Expected: true, Actual: false},
+			{Setting: "billing_email", Expected: "it@example.com", Actual: "billing@example.com"},
+		}),
+		Entry("more filled seats than allowed", OrganizationBaseline{MaxFilledSeats: number(40)}, []SettingDeviation{
+			{Setting: "plan_filled_seats", Expected: 40, Actual: 42},
+		}),
+	)
+})
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+// RepositoryPatch is a partial Repository, settings that are nil are left unchanged.
+type RepositoryPatch struct {
+	// Name selects the repository the patch applies to, patches without a name apply to all repositories.
+	Name                string  `json:"name,omitempty"`
+	Description         *string `json:"description,omitempty"`
+	Homepage            *string `json:"homepage,omitempty"`
+	DefaultBranch       *string `json:"default_branch,omitempty"`
+	Private             *bool   `json:"private,omitempty"`
+	Template            *bool   `json:"template,omitempty"`
+	IssuesEnabled       *bool   `json:"issues_enabled,omitempty"`
+	WikiEnabled         *bool   `json:"wiki_enabled,omitempty"`
+	ProjectsEnabled     *bool   `json:"projects_enabled,omitempty"`
+	AllowRebaseMerge    *bool   `json:"allow_rebase_merge,omitempty"`
+	AllowSquashMerge    *bool   `json:"allow_squash_merge,omitempty"`
+	AllowMergeCommit    *bool   `json:"allow_merge_commit,omitempty"`
+	DeleteBranchOnMerge *bool   `json:"delete_branch_on_merge,omitempty"`
+}
+
+type SettingChange struct {
+	Setting string      `json:"setting,omitempty"`
+	Before  interface{} `json:"before"`
+	After   interface{} `json:"after"`
+}
+
+func (s SettingChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", s.Setting, s.Before, s.After)
+}
+
+// Changes returns the settings of the repository the patch would change.
+func (s RepositoryPatch) Changes(r *Repository) []SettingChange {
+	var changes []SettingChange
+	str := func(setting string, before string, after *string) {
+		if after != nil && *after != before {
+			changes = append(changes, SettingChange{Setting: setting, Before: before, After: *after})
+		}
+	}
+	boolean := func(setting string, before bool, after *bool) {
+		if after != nil && *after != before {
+			changes = append(changes, SettingChange{Setting: setting, Before: before, After: *after})
+		}
+	}
+	str("description", r.Description, s.Description)
+	str("homepage", r.Homepage, s.Homepage)
+	str("default_branch", r.DefaultBranch, s.DefaultBranch)
+	boolean("private", r.Private, s.Private)
+	boolean("template", r.Template, s.Template)
+	boolean("issues_enabled", r.IssuesEnabled, s.IssuesEnabled)
+	boolean("wiki_enabled", r.WikiEnabled, s.WikiEnabled)
+	boolean("projects_enabled", r.ProjectsEnabled, s.ProjectsEnabled)
+	boolean("allow_rebase_merge", r.AllowRebaseMerge, s.AllowRebaseMerge)
+	boolean("allow_squash_merge", r.AllowSquashMerge, s.AllowSquashMerge)
+	boolean("allow_merge_commit", r.AllowMergeCommit, s.AllowMergeCommit)
+	boolean("delete_branch_on_merge", r.DeleteBranchOnMerge, s.DeleteBranchOnMerge)
+	return changes
+}
+
+// UpdateRepository applies the patch to the repository and updates it with the settings returned by Github.
+func (s *GithubClient) UpdateRepository(repository *Repository, patch RepositoryPatch) error {
+	result, _, err := s.v3Client.Repositories.Edit(context.Background(), repository.Owner, repository.Name, &gh3.Repository{
+		Description:         patch.Description,
+		Homepage:            patch.Homepage,
+		DefaultBranch:       patch.DefaultBranch,
+		Private:             patch.Private,
+		IsTemplate:          patch.Template,
+		HasIssues:           patch.IssuesEnabled,
+		HasWiki:             patch.WikiEnabled,
+		HasProjects:         patch.ProjectsEnabled,
+		AllowRebaseMerge:    patch.AllowRebaseMerge,
+		AllowSquashMerge:    patch.AllowSquashMerge,
+		AllowMergeCommit:    patch.AllowMergeCommit,
+		DeleteBranchOnMerge: patch.DeleteBranchOnMerge,
+	})
+	if err != nil {
+		return err
+	}
+	updated := fromGh3Repository(result)
+	repository.Description = updated.Description
+	repository.Homepage = updated.Homepage
+	repository.DefaultBranch = updated.DefaultBranch
+	repository.Private = updated.Private
+	repository.Template = updated.Template
+	repository.IssuesEnabled = updated.IssuesEnabled
+	repository.WikiEnabled = updated.WikiEnabled
+	repository.ProjectsEnabled = updated.ProjectsEnabled
+	repository.AllowRebaseMerge = updated.AllowRebaseMerge
+	repository.AllowSquashMerge = updated.AllowSquashMerge
+	repository.AllowMergeCommit = updated.AllowMergeCommit
+	repository.DeleteBranchOnMerge = updated.DeleteBranchOnMerge
+	return nil
+}
+
+// RepositoryUpdatePlan plans the updates needed to apply the patches to the repositories. Each action lists the
+// changed settings with their values before and after, repositories the patches would not change are left out.
+func (s *GithubClient) RepositoryUpdatePlan(repositories []*Repository, patches ...RepositoryPatch) (*Plan, error) {
+	plan := &Plan{}
+	for _, patch := range patches {
+		patch := patch
+		matched := false
+		for _, repository := range repositories {
+			repository := repository
+			if patch.Name != "" && !strings.EqualFold(patch.Name, repository.Name) {
+				continue
+			}
+			matched = true
+			changes := patch.Changes(repository)
+			if len(changes) == 0 {
+				continue
+			}
+			var details []string
+			for _, c := range changes {
+				details = append(details, c.String())
+			}
+			plan.Add("update-repository", repository.Name, strings.Join(details, ", "), "", func() error {
+				return s.UpdateRepository(repository, patch)
+			})
+		}
+		if patch.Name != "" && !matched {
+			return nil, fmt.Errorf("repository %s does not exist", patch.Name)
+		}
+	}
+	return plan, nil
+}
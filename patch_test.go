@@ -0,0 +1,52 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("RepositoryPatch", func() {
+	repository := &Repository{
+		Name:             "api",
+		Description:      "The API",
+		DefaultBranch:    "main",
+		IssuesEnabled:    true,
+		AllowMergeCommit: true,
+	}
+
+	DescribeTable("lists the settings it changes",
+		func(patch RepositoryPatch, expected []string) {
+			var changes []string
+			for _, change := range patch.Changes(repository) {
+				changes = append(changes, change.String())
+			}
+			Expect(changes).To(Equal(expected))
+		},
+		Entry("nothing for an empty patch", RepositoryPatch{}, nil),
+		Entry("nothing for unchanged settings", RepositoryPatch{
+			Description:   str("The API"),
+			IssuesEnabled: boolean(true),
+			Private:       boolean(false),
+		}, nil),
+		Entry("changed strings", RepositoryPatch{
+			Description:   str(""),
+			DefaultBranch: str("develop"),
+			Homepage:      str("https://example.com"),
+		}, []string{
+			"description: The API -> ",
+			"homepage:  -> https://example.com",
+			"default_branch: main -> develop",
+		}),
+		Entry("changed flags", RepositoryPatch{
+			AllowMergeCommit:    boolean(false),
+			DeleteBranchOnMerge: boolean(true),
+			IssuesEnabled:       boolean(true),
+		}, []string{
+			"allow_merge_commit: true -> false",
+			"delete_branch_on_merge: false -> true",
+		}),
+	)
+})
@@ -0,0 +1,50 @@
+package github
+
+import log "github.com/mtrense/soil/logging"
+
+type Plan struct {
+	Actions []*PlanAction `json:"actions,omitempty"`
+}
+
+type PlanAction struct {
+	Operation string `json:"operation,omitempty"`
+	Target    string `json:"target,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Done      bool   `json:"done"`
+	Error     string `json:"error,omitempty"`
+	execute   func() error
+}
+
+func (s *Plan) Add(operation, target, detail, reason string, execute func() error) *PlanAction {
+	action := &PlanAction{
+		Operation: operation,
+		Target:    target,
+		Detail:    detail,
+		Reason:    reason,
+		execute:   execute,
+	}
+	s.Actions = append(s.Actions, action)
+	return action
+}
+
+func (s *Plan) Empty() bool {
+	return len(s.Actions) == 0
+}
+
+// Execute runs all actions of the plan in order and stops at the first failing one. Every action records whether it
+// has been carried out, so the plan can be printed afterwards as a manifest of what was done.
+func (s *Plan) Execute() error {
+	for _, action := range s.Actions {
+		if action.Done || action.execute == nil {
+			continue
+		}
+		log.L().Info().Str("operation", action.Operation).Str("target", action.Target).Msg("Executing")
+		if err := action.execute(); err != nil {
+			action.Error = err.Error()
+			return err
+		}
+		action.Done = true
+	}
+	return nil
+}
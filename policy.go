@@ -0,0 +1,196 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TeamPolicies describe which permissions teams must or must not have on repositories, e.g.
+//
+//	owner_topic_prefix: team-
+//	policies:
+//	  - name: platform maintains infrastructure
+//	    team: platform
+//	    topic: infra
+//	    require: maintain
+//	  - name: no admin outside own repositories
+//	    team: "*"
+//	    not_owned: true
+//	    max: write
+type TeamPolicies struct {
+	// OwnerTopicPrefix marks repositories as owned by a team if they carry the topic prefix+slug.
+	OwnerTopicPrefix string       `json:"owner_topic_prefix,omitempty" yaml:"owner_topic_prefix,omitempty"`
+	Policies         []TeamPolicy `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+type TeamPolicy struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Team is the slug of the team the policy applies to, or "*" for all teams.
+	Team string `json:"team,omitempty" yaml:"team,omitempty"`
+	// Topic restricts the policy to repositories with the given topic.
+	Topic string `json:"topic,omitempty" yaml:"topic,omitempty"`
+	// Pattern restricts the policy to repositories whose name matches the glob.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// NotOwned restricts the policy to repositories not owned by the team.
+	NotOwned bool `json:"not_owned,omitempty" yaml:"not_owned,omitempty"`
+	// Require is the minimum permission the team must have.
+	Require string `json:"require,omitempty" yaml:"require,omitempty"`
+	// Max is the maximum permission the team may have, "none" forbids any access.
+	Max string `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+func ReadTeamPolicies(r io.Reader) (TeamPolicies, error) {
+	var policies TeamPolicies
+	err := yaml.NewDecoder(r).Decode(&policies)
+	return policies, err
+}
+
+type PolicyViolation struct {
+	Policy     string `json:"policy,omitempty"`
+	Team       string `json:"team,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	Expected   string `json:"expected,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+	Via        string `json:"via,omitempty"`
+}
+
+func (s TeamPolicy) appliesTo(team *TeamNode, repository *Repository, ownerTopicPrefix string) bool {
+	if s.Team != "*" && !strings.EqualFold(s.Team, team.Slug) {
+		return false
+	}
+	if s.Pattern != "" {
+		if matched, _ := path.Match(s.Pattern, repository.Name); !matched {
+			return false
+		}
+	}
+	if s.Topic != "" && !containsString(repository.Topics, s.Topic) {
+		return false
+	}
+	if s.NotOwned && containsString(repository.Topics, ownerTopicPrefix+team.Slug) {
+		return false
+	}
+	return true
+}
+
+// Evaluate checks the effective permissions of the given teams (including permissions inherited from parent teams)
+// on the given repositories against the policies. A policy for a team that does not exist is a violation itself.
+func (s TeamPolicies) Evaluate(roots []*TeamNode, repositories []*Repository) []PolicyViolation {
+	var violations []PolicyViolation
+	slugs := make(map[string]bool)
+	for _, root := range roots {
+		root.Walk(func(team *TeamNode, depth int) {
+			slugs[strings.ToLower(team.Slug)] = true
+			direct := make(map[string]string)
+			for _, r := range team.Repositories {
+				direct[r.Name] = r.Permission
+			}
+			inherited := make(map[string]*InheritedTeamRepository)
+			for _, r := range team.InheritedRepositories {
+				inherited[r.Name] = r
+			}
+			for _, repository := range repositories {
+				actual, via := direct[repository.Name], ""
+				if r, ok := inherited[repository.Name]; ok {
+					actual, via = r.Permission, r.Via
+				}
+				for _, policy := range s.Policies {
+					if !policy.appliesTo(team, repository, s.OwnerTopicPrefix) {
+						continue
+					}
+					violation := PolicyViolation{
+						Policy:     policy.Name,
+						Team:       team.Slug,
+						Repository: repository.Name,
+						Actual:     strings.ToLower(actual),
+						Via:        via,
+					}
+					if policy.Require != "" && permissionRank(actual) < permissionRank(policy.Require) {
+						violation.Expected = "at least " + strings.ToLower(policy.Require)
+						violations = append(violations, violation)
+					}
+					if policy.Max != "" && permissionRank(actual) > permissionRank(policy.Max) {
+						violation.Expected = "at most " + strings.ToLower(policy.Max)
+						violations = append(violations, violation)
+					}
+				}
+			}
+		})
+	}
+	for _, policy := range s.Policies {
+		if policy.Team != "*" && !slugs[strings.ToLower(policy.Team)] {
+			violations = append(violations, PolicyViolation{
+				Policy:   policy.Name,
+				Team:     policy.Team,
+				Expected: "existing team",
+				Actual:   "missing",
+			})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Team != violations[j].Team {
+			return violations[i].Team < violations[j].Team
+		}
+		return violations[i].Repository < violations[j].Repository
+	})
+	return violations
+}
+
+func (s *GithubClient) TeamPolicyAudit(org string, policies TeamPolicies, filters ...RepositoryFilter) ([]PolicyViolation, error) {
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.LoadTeamRepositories(org, teams...); err != nil {
+		return nil, err
+	}
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return nil, err
+	}
+	var active []*Repository
+	for _, r := range FilterRepositories(repositories, filters...) {
+		if !r.Archived {
+			active = append(active, r)
+		}
+	}
+	return policies.Evaluate(BuildTeamTree(teams), active), nil
+}
+
+// TeamPolicyPlan plans the grants and revokes needed to resolve all policy violations. Violations caused by a
+// permission inherited from a parent team can only be resolved on the parent and are left out of the plan, just like
+// policies for teams that do not exist.
+func (s *GithubClient) TeamPolicyPlan(org string, policies TeamPolicies, filters ...RepositoryFilter) (*Plan, error) {
+	violations, err := s.TeamPolicyAudit(org, policies, filters...)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	for _, v := range violations {
+		v := v
+		reason := fmt.Sprintf("policy %q expects %s, has %s", v.Policy, v.Expected, v.Actual)
+		switch {
+		case v.Via != "" || v.Repository == "":
+			continue
+		case strings.HasPrefix(v.Expected, "at least "):
+			permission := restPermission(strings.TrimPrefix(v.Expected, "at least "))
+			plan.Add("grant-team-repository", v.Team, v.Repository+" with "+permission, reason, func() error {
+				return s.GrantTeamRepository(org, v.Team, org, v.Repository, permission)
+			})
+		case v.Expected == "at most none":
+			plan.Add("revoke-team-repository", v.Team, v.Repository, reason, func() error {
+				return s.RevokeTeamRepository(org, v.Team, org, v.Repository)
+			})
+		default:
+			permission := restPermission(strings.TrimPrefix(v.Expected, "at most "))
+			plan.Add("grant-team-repository", v.Team, v.Repository+" with "+permission, reason, func() error {
+				return s.GrantTeamRepository(org, v.Team, org, v.Repository, permission)
+			})
+		}
+	}
+	return plan, nil
+}
@@ -0,0 +1,43 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("TeamPolicies", func() {
+	roots := func() []*TeamNode {
+		return BuildTeamTree([]*Team{
+			{ID: "1", Slug: "platform", Repositories: []*TeamRepository{{Owner: "org", Name: "infra", Permission: "WRITE"}}},
+			{ID: "2", Slug: "web", Parent: "1", Repositories: []*TeamRepository{{Owner: "org", Name: "site", Permission: "ADMIN"}}},
+		})
+	}
+	repositories := []*Repository{
+		{Owner: "org", Name: "infra", Topics: []string{"infra"}},
+		{Owner: "org", Name: "site", Topics: []string{"team-web"}},
+	}
+
+	It("reports missing and excess permissions, including inherited ones", func() {
+		policies := TeamPolicies{
+			OwnerTopicPrefix: "team-",
+			Policies: []TeamPolicy{
+				{Name: "maintain infra", Team: "platform", Topic: "infra", Require: "maintain"},
+				{Name: "no admin outside own", Team: "*", NotOwned: true, Max: "write"},
+				{Name: "no write on infra", Team: "web", Topic: "infra", Max: "read"},
+			},
+		}
+		Expect(policies.Evaluate(roots(), repositories)).To(Equal([]PolicyViolation{
+			{Policy: "maintain infra", Team: "platform", Repository: "infra", Expected: "at least maintain", Actual: "write"},
+			{Policy: "no write on infra", Team: "web", Repository: "infra", Expected: "at most read", Actual: "write", Via: "platform"},
+		}))
+	})
+
+	It("reports policies for teams that do not exist", func() {
+		policies := TeamPolicies{Policies: []TeamPolicy{{Name: "typo", Team: "platfrom", Require: "read"}}}
+		Expect(policies.Evaluate(roots(), repositories)).To(Equal([]PolicyViolation{
+			{Policy: "typo", Team: "platfrom", Expected: "existing team", Actual: "missing"},
+		}))
+	})
+})
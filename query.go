@@ -19,6 +19,7 @@ type Query struct {
 	target     interface{}
 	variables  map[string]interface{}
 	cursorName string
+	preview    bool
 }
 
 func (s *GithubClient) Query(target interface{}) *Query {
@@ -60,8 +61,26 @@ func (s *Query) Bool(name string, value bool) *Query {
 	return s
 }
 
+func (s *Query) Var(name string, value interface{}) *Query {
+	s.variables[name] = value
+	return s
+}
+
+// Preview runs the query with the dependency graph schema preview enabled.
+func (s *Query) Preview() *Query {
+	s.preview = true
+	return s
+}
+
+func (s *Query) v4Client() *githubv4.Client {
+	if s.preview {
+		return s.client.v4PreviewClient
+	}
+	return s.client.v4Client
+}
+
 func (s *Query) Run() error {
-	if err := s.client.v4Client.Query(context.Background(), s.target, s.variables); err != nil {
+	if err := s.v4Client().Query(context.Background(), s.target, s.variables); err != nil {
 		return err
 	}
 	return nil
@@ -69,7 +88,7 @@ func (s *Query) Run() error {
 
 func (s *Query) RunPaginated(handler func() PageInfo) error {
 	for {
-		if err := s.client.v4Client.Query(context.Background(), s.target, s.variables); err != nil {
+		if err := s.v4Client().Query(context.Background(), s.target, s.variables); err != nil {
 			return err
 		}
 		pageInfo := handler()
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	log "github.com/mtrense/soil/logging"
@@ -24,6 +25,7 @@ type Repository struct {
 	Archived              bool                   `json:"archived,omitempty"`
 	Disabled              bool                   `json:"disabled,omitempty"`
 	Template              bool                   `json:"template,omitempty"`
+	Fork                  bool                   `json:"fork,omitempty"`
 	IssuesEnabled         bool                   `json:"issues_enabled,omitempty"`
 	WikiEnabled           bool                   `json:"wiki_enabled,omitempty"`
 	ProjectsEnabled       bool                   `json:"projects_enabled,omitempty"`
@@ -44,6 +46,147 @@ type Repository struct {
 	PrimaryLanguage       string                 `json:"primary_language,omitempty"`
 	Languages             []Language             `json:"languages,omitempty"`
 	Workflows             []Workflow             `json:"workflows,omitempty"`
+	InteractionLimit      *InteractionLimit      `json:"interaction_limit,omitempty"`
+	Webhooks              []*Webhook             `json:"webhooks,omitempty"`
+	DeployKeys            []PublicKey            `json:"deploy_keys,omitempty"`
+	Secrets               []ActionsSecret        `json:"secrets,omitempty"`
+	Variables             []ActionsVariable      `json:"variables,omitempty"`
+}
+
+type repositoryNode struct {
+	Owner struct {
+		Login githubv4.String
+	}
+	Name             githubv4.String
+	CreatedAt        githubv4.DateTime
+	DefaultBranchRef struct {
+		Name githubv4.String
+	}
+	Description          githubv4.String
+	DescriptionHTML      githubv4.String `graphql:"descriptionHTML"`
+	ShortDescriptionHTML githubv4.String `graphql:"shortDescriptionHTML"`
+	HasIssuesEnabled     githubv4.Boolean
+	HasProjectsEnabled   githubv4.Boolean
+	HasWikiEnabled       githubv4.Boolean
+	HomepageURL          githubv4.String
+	URL                  githubv4.String
+	SshURL               githubv4.String
+	IsArchived           githubv4.Boolean
+	IsDisabled           githubv4.Boolean
+	IsEmpty              githubv4.Boolean
+	IsFork               githubv4.Boolean
+	IsLocked             githubv4.Boolean
+	IsMirror             githubv4.Boolean
+	IsPrivate            githubv4.Boolean
+	IsTemplate           githubv4.Boolean
+	LicenseInfo          struct {
+		Name githubv4.String
+	}
+	Labels struct {
+		TotalCount githubv4.Int
+	}
+	Languages struct {
+		TotalCount githubv4.Int
+	}
+	RebaseMergeAllowed  githubv4.Boolean
+	MergeCommitAllowed  githubv4.Boolean
+	SquashMergeAllowed  githubv4.Boolean
+	DeleteBranchOnMerge githubv4.Boolean
+	DiskUsage           githubv4.Int
+	PushedAt            githubv4.DateTime
+	UpdatedAt           githubv4.DateTime
+	PrimaryLanguage     struct {
+		Name githubv4.String
+	}
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name githubv4.String
+			}
+		}
+	} `graphql:"repositoryTopics(first: 20)"`
+}
+
+func (node repositoryNode) toRepository() *Repository {
+	var topics []string
+	for _, t := range node.RepositoryTopics.Nodes {
+		topics = append(topics, string(t.Topic.Name))
+	}
+	return &Repository{
+		Owner:               string(node.Owner.Login),
+		Name:                string(node.Name),
+		Private:             bool(node.IsPrivate),
+		Description:         string(node.Description),
+		Homepage:            string(node.HomepageURL),
+		URL:                 string(node.URL),
+		Ssh:                 string(node.SshURL),
+		DefaultBranch:       string(node.DefaultBranchRef.Name),
+		Archived:            bool(node.IsArchived),
+		Disabled:            bool(node.IsDisabled),
+		Template:            bool(node.IsTemplate),
+		Fork:                bool(node.IsFork),
+		IssuesEnabled:       bool(node.HasIssuesEnabled),
+		ProjectsEnabled:     bool(node.HasProjectsEnabled),
+		WikiEnabled:         bool(node.HasWikiEnabled),
+		AllowRebaseMerge:    bool(node.RebaseMergeAllowed),
+		AllowSquashMerge:    bool(node.SquashMergeAllowed),
+		AllowMergeCommit:    bool(node.MergeCommitAllowed),
+		DeleteBranchOnMerge: bool(node.DeleteBranchOnMerge),
+		LicenseName:         string(node.LicenseInfo.Name),
+		Topics:              topics,
+		DiskUsage:           int(node.DiskUsage),
+		PushedAt:            node.PushedAt.Time,
+		UpdatedAt:           node.UpdatedAt.Time,
+		Age:                 int(time.Now().Sub(node.PushedAt.Time).Seconds()),
+		PrimaryLanguage:     string(node.PrimaryLanguage.Name),
+	}
+}
+
+// GetUserRepositories returns the repositories the user is affiliated with. Valid affiliations are "OWNER",
+// "COLLABORATOR" and "ORGANIZATION_MEMBER", all of them are used if none are given. Please note that Github only
+// reveals affiliations other than OWNER for the authenticated user.
+func (s *GithubClient) GetUserRepositories(login string, affiliations ...string) ([]*Repository, error) {
+	var query struct {
+		User struct {
+			Repositories struct {
+				PageInfo PageInfo
+				Nodes    []repositoryNode
+			} `graphql:"repositories(first: 100, after: $cursor, affiliations: $affiliations, ownerAffiliations: $affiliations)"`
+		} `graphql:"user(login: $login)"`
+	}
+	if len(affiliations) == 0 {
+		affiliations = []string{"OWNER", "COLLABORATOR", "ORGANIZATION_MEMBER"}
+	}
+	var values []githubv4.RepositoryAffiliation
+	for _, a := range affiliations {
+		values = append(values, githubv4.RepositoryAffiliation(strings.ToUpper(strings.TrimSpace(a))))
+	}
+	var repositories []*Repository
+	return repositories, s.Query(&query).Str("login", login).Var("affiliations", values).Cursor("cursor").RunPaginated(func() PageInfo {
+		for _, node := range query.User.Repositories.Nodes {
+			repositories = append(repositories, node.toRepository())
+		}
+		return query.User.Repositories.PageInfo
+	})
+}
+
+// GetOwnerRepositories returns the repositories owned by the given user or organization.
+func (s *GithubClient) GetOwnerRepositories(login string) ([]*Repository, error) {
+	var query struct {
+		RepositoryOwner struct {
+			Repositories struct {
+				PageInfo PageInfo
+				Nodes    []repositoryNode
+			} `graphql:"repositories(first: 100, after: $cursor, ownerAffiliations: OWNER)"`
+		} `graphql:"repositoryOwner(login: $login)"`
+	}
+	var repositories []*Repository
+	return repositories, s.Query(&query).Str("login", login).Cursor("cursor").RunPaginated(func() PageInfo {
+		for _, node := range query.RepositoryOwner.Repositories.Nodes {
+			repositories = append(repositories, node.toRepository())
+		}
+		return query.RepositoryOwner.Repositories.PageInfo
+	})
 }
 
 type Language struct {
@@ -86,6 +229,39 @@ func (s *GithubClient) loadRepositoryLanguages(repository *Repository) error {
 	})
 }
 
+// SetRepositoryTopics replaces all topics of the repository.
+func (s *GithubClient) SetRepositoryTopics(repository *Repository, topics ...string) error {
+	if topics == nil {
+		topics = []string{}
+	}
+	result, _, err := s.v3Client.Repositories.ReplaceAllTopics(context.Background(), repository.Owner, repository.Name, topics)
+	if err != nil {
+		return err
+	}
+	repository.Topics = result
+	return nil
+}
+
+func (s *GithubClient) AddRepositoryTopics(repository *Repository, topics ...string) error {
+	result := append([]string{}, repository.Topics...)
+	for _, t := range topics {
+		if !containsString(result, t) {
+			result = append(result, t)
+		}
+	}
+	return s.SetRepositoryTopics(repository, result...)
+}
+
+func (s *GithubClient) RemoveRepositoryTopics(repository *Repository, topics ...string) error {
+	var result []string
+	for _, t := range repository.Topics {
+		if !containsString(topics, t) {
+			result = append(result, t)
+		}
+	}
+	return s.SetRepositoryTopics(repository, result...)
+}
+
 type Collaborator struct {
 	Login               string             `json:"login,omitempty"`
 	EffectivePermission string             `json:"effective_permission,omitempty"`
@@ -287,6 +463,34 @@ func (s *GithubClient) EnableVulnerabilityAlerts(owner string, repository string
 	return err
 }
 
+func (s *GithubClient) LoadRepositoryInteractionLimits(repositories ...*Repository) error {
+	for _, repository := range repositories {
+		if err := s.loadRepositoryInteractionLimit(repository); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GithubClient) loadRepositoryInteractionLimit(repository *Repository) error {
+	restriction, _, err := s.v3Client.Interactions.GetRestrictionsForRepo(context.Background(), repository.Owner, repository.Name)
+	if err != nil {
+		return err
+	}
+	repository.InteractionLimit = fromGh3InteractionRestriction(restriction)
+	return nil
+}
+
+func (s *GithubClient) SetRepositoryInteractionLimit(owner string, repository string, limit string) error {
+	_, _, err := s.v3Client.Interactions.UpdateRestrictionsForRepo(context.Background(), owner, repository, limit)
+	return err
+}
+
+func (s *GithubClient) RemoveRepositoryInteractionLimit(owner string, repository string) error {
+	_, err := s.v3Client.Interactions.RemoveRestrictionsFromRepo(context.Background(), owner, repository)
+	return err
+}
+
 type Workflow struct {
 	Name         string `json:"name,omitempty"`
 	Path         string `json:"path,omitempty"`
@@ -325,13 +529,3 @@ func (s *GithubClient) loadRepositoryWorkflows(repository *Repository) error {
 		return resp, err
 	})
 }
-
-func (s *GithubClient) CreateRepository(org string, r *Repository) error {
-	_, _, err := s.v3Client.Repositories.Create(context.Background(), org, &gh3.Repository{
-		Name:        &r.Name,
-		Private:     &r.Private,
-		Description: boxString(r.Description),
-		Homepage:    boxString(r.Homepage),
-	})
-	return err
-}
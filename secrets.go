@@ -0,0 +1,164 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+const (
+	VisibilityAll      = "all"
+	VisibilityPrivate  = "private"
+	VisibilitySelected = "selected"
+)
+
+// ActionsSecret describes an Actions secret, Github never reveals its value.
+type ActionsSecret struct {
+	Name        string    `json:"name,omitempty"`
+	Environment string    `json:"environment,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	// Visibility and SelectedRepositories are only set for secrets of an organization.
+	Visibility           string   `json:"visibility,omitempty"`
+	SelectedRepositories []string `json:"selected_repositories,omitempty"`
+}
+
+type ActionsVariable struct {
+	Name                 string    `json:"name,omitempty"`
+	Value                string    `json:"value,omitempty"`
+	Environment          string    `json:"environment,omitempty"`
+	CreatedAt            time.Time `json:"created_at,omitempty"`
+	UpdatedAt            time.Time `json:"updated_at,omitempty"`
+	Visibility           string    `json:"visibility,omitempty"`
+	SelectedRepositories []string  `json:"selected_repositories,omitempty"`
+}
+
+type actionsPage struct {
+	Secrets      []ActionsSecret   `json:"secrets"`
+	Variables    []ActionsVariable `json:"variables"`
+	Repositories []struct {
+		Name string `json:"name"`
+	} `json:"repositories"`
+	Environments []struct {
+		Name string `json:"name"`
+	} `json:"environments"`
+}
+
+// listActions pages through the given Actions endpoint. go-github does not know about environments and variables
+// yet, so all of them are requested directly. Endpoints that do not exist for the repository are treated as empty.
+func (s *GithubClient) listActions(path string, fn func(page actionsPage)) error {
+	return s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		req, err := s.v3Client.NewRequest("GET", fmt.Sprintf("%s?per_page=%d&page=%d", path, lo.PerPage, lo.Page), nil)
+		if err != nil {
+			return nil, err
+		}
+		var page actionsPage
+		resp, err := s.v3Client.Do(context.Background(), req, &page)
+		if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+			return resp, nil
+		}
+		fn(page)
+		return resp, err
+	})
+}
+
+func (s *GithubClient) LoadRepositoryActionsSecrets(repositories ...*Repository) error {
+	for _, repository := range repositories {
+		if err := s.loadRepositoryActionsSecrets(repository); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRepositoryActionsSecrets loads the secrets and variables of the repository and all of its environments.
+func (s *GithubClient) loadRepositoryActionsSecrets(repository *Repository) error {
+	repository.Secrets = nil
+	repository.Variables = nil
+	base := fmt.Sprintf("repos/%v/%v", repository.Owner, repository.Name)
+	collect := func(environment string) func(page actionsPage) {
+		return func(page actionsPage) {
+			for _, secret := range page.Secrets {
+				secret.Environment = environment
+				repository.Secrets = append(repository.Secrets, secret)
+			}
+			for _, variable := range page.Variables {
+				variable.Environment = environment
+				repository.Variables = append(repository.Variables, variable)
+			}
+		}
+	}
+	if err := s.listActions(base+"/actions/secrets", collect("")); err != nil {
+		return err
+	}
+	if err := s.listActions(base+"/actions/variables", collect("")); err != nil {
+		return err
+	}
+	var environments []string
+	if err := s.listActions(base+"/environments", func(page actionsPage) {
+		for _, e := range page.Environments {
+			environments = append(environments, e.Name)
+		}
+	}); err != nil {
+		return err
+	}
+	for _, environment := range environments {
+		path := base + "/environments/" + url.PathEscape(environment)
+		if err := s.listActions(path+"/secrets", collect(environment)); err != nil {
+			return err
+		}
+		if err := s.listActions(path+"/variables", collect(environment)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetOrganizationActionsSecrets returns the secrets and variables of the organization. For secrets and variables
+// visible to selected repositories only, the names of these repositories are included.
+func (s *GithubClient) GetOrganizationActionsSecrets(org string) ([]ActionsSecret, []ActionsVariable, error) {
+	var secrets []ActionsSecret
+	var variables []ActionsVariable
+	base := fmt.Sprintf("orgs/%v/actions", org)
+	if err := s.listActions(base+"/secrets", func(page actionsPage) {
+		secrets = append(secrets, page.Secrets...)
+	}); err != nil {
+		return nil, nil, err
+	}
+	if err := s.listActions(base+"/variables", func(page actionsPage) {
+		variables = append(variables, page.Variables...)
+	}); err != nil {
+		return nil, nil, err
+	}
+	selectedRepositories := func(path string) ([]string, error) {
+		var names []string
+		err := s.listActions(path, func(page actionsPage) {
+			for _, r := range page.Repositories {
+				names = append(names, r.Name)
+			}
+		})
+		sort.Strings(names)
+		return names, err
+	}
+	var err error
+	for i := range secrets {
+		if secrets[i].Visibility == VisibilitySelected {
+			if secrets[i].SelectedRepositories, err = selectedRepositories(base + "/secrets/" + url.PathEscape(secrets[i].Name) + "/repositories"); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	for i := range variables {
+		if variables[i].Visibility == VisibilitySelected {
+			if variables[i].SelectedRepositories, err = selectedRepositories(base + "/variables/" + url.PathEscape(variables[i].Name) + "/repositories"); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return secrets, variables, nil
+}
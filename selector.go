@@ -0,0 +1,149 @@
+package github
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RepositoryFilter decides whether a repository is part of a selection.
+type RepositoryFilter func(r *Repository) bool
+
+// FilterRepositories returns all repositories matching all of the given filters.
+func FilterRepositories(repositories []*Repository, filters ...RepositoryFilter) []*Repository {
+	if len(filters) == 0 {
+		return repositories
+	}
+	var selected []*Repository
+	for _, r := range repositories {
+		matches := true
+		for _, filter := range filters {
+			matches = matches && filter(r)
+		}
+		if matches {
+			selected = append(selected, r)
+		}
+	}
+	return selected
+}
+
+func WithTopic(topic string) RepositoryFilter {
+	return func(r *Repository) bool {
+		return containsString(r.Topics, topic)
+	}
+}
+
+// WithName matches the repository name against a glob pattern, or against a regular expression if the pattern is
+// enclosed in slashes (e.g. "/^api-.*$/").
+func WithName(pattern string) (RepositoryFilter, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, err
+		}
+		return func(r *Repository) bool {
+			return re.MatchString(r.Name)
+		}, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return func(r *Repository) bool {
+		matched, _ := path.Match(pattern, r.Name)
+		return matched
+	}, nil
+}
+
+func WithPushedBefore(t time.Time) RepositoryFilter {
+	return func(r *Repository) bool {
+		return r.PushedAt.Before(t)
+	}
+}
+
+// selectorTerm finds the commas separating predicates, which are followed by the key of the next predicate.
+var selectorTerm = regexp.MustCompile(`,\s*[A-Za-z_]+\s*!?=`)
+
+// ParseRepositorySelector parses a comma separated list of predicates that all have to match, e.g.
+// "name=api-*,archived=false,language=Go,pushed_before=180d". Supported keys are name, archived, private, fork,
+// template, language, topic and pushed_before (a date like 2006-01-02 or a number of days). Predicates can be negated
+// by using != instead of =. Values may contain commas as long as these are not followed by key= (e.g.
+// "name=/^api-[a-z]{2,4}$/").
+func ParseRepositorySelector(selector string) ([]RepositoryFilter, error) {
+	var predicates []string
+	start := 0
+	for _, match := range selectorTerm.FindAllStringIndex(selector, -1) {
+		predicates = append(predicates, selector[start:match[0]])
+		start = match[0] + 1
+	}
+	predicates = append(predicates, selector[start:])
+	var filters []RepositoryFilter
+	for _, predicate := range predicates {
+		predicate = strings.TrimSpace(predicate)
+		if predicate == "" {
+			continue
+		}
+		i := strings.Index(predicate, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid predicate %q, expected key=value", predicate)
+		}
+		key, value, negate := strings.TrimSpace(predicate[:i]), strings.TrimSpace(predicate[i+1:]), false
+		if strings.HasSuffix(key, "!") {
+			key, negate = strings.TrimSpace(strings.TrimSuffix(key, "!")), true
+		}
+		filter, err := parseRepositoryPredicate(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			positive := filter
+			filter = func(r *Repository) bool {
+				return !positive(r)
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+func parseRepositoryPredicate(key, value string) (RepositoryFilter, error) {
+	flag := func(get func(r *Repository) bool) (RepositoryFilter, error) {
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return func(r *Repository) bool {
+			return get(r) == expected
+		}, nil
+	}
+	switch key {
+	case "name":
+		return WithName(value)
+	case "archived":
+		return flag(func(r *Repository) bool { return r.Archived })
+	case "private":
+		return flag(func(r *Repository) bool { return r.Private })
+	case "fork":
+		return flag(func(r *Repository) bool { return r.Fork })
+	case "template":
+		return flag(func(r *Repository) bool { return r.Template })
+	case "language":
+		return func(r *Repository) bool {
+			return strings.EqualFold(r.PrimaryLanguage, value)
+		}, nil
+	case "topic":
+		return WithTopic(value), nil
+	case "pushed_before":
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return WithPushedBefore(time.Now().AddDate(0, 0, -days)), nil
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: expected a date or a number of days", key)
+		}
+		return WithPushedBefore(t), nil
+	}
+	return nil, fmt.Errorf("unknown selector key %q", key)
+}
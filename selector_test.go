@@ -0,0 +1,68 @@
+package github_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("Repository selection", func() {
+	now := time.Now()
+	repositories := []*Repository{
+		{Name: "api-users", PrimaryLanguage: "Go", Topics: []string{"team-web"}, PushedAt: now.AddDate(0, 0, -10)},
+		{Name: "api-billing", PrimaryLanguage: "Go", Private: true, PushedAt: now.AddDate(0, 0, -400)},
+		{Name: "website", PrimaryLanguage: "TypeScript", Topics: []string{"team-web"}, PushedAt: now.AddDate(0, 0, -200)},
+		{Name: "legacy", Archived: true, Fork: true, PushedAt: time.Date(2015, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "template-go", Template: true, PushedAt: now.AddDate(0, 0, -30)},
+	}
+	names := func(selected []*Repository) []string {
+		var result []string
+		for _, r := range selected {
+			result = append(result, r.Name)
+		}
+		return result
+	}
+
+	DescribeTable("selects repositories",
+		func(selector string, expected []string) {
+			filters, err := ParseRepositorySelector(selector)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names(FilterRepositories(repositories, filters...))).To(Equal(expected))
+		},
+		Entry("all for an empty selector", "", []string{"api-users", "api-billing", "website", "legacy", "template-go"}),
+		Entry("by name glob", "name=api-*", []string{"api-users", "api-billing"}),
+		Entry("by name regular expression", "name=/^(web|leg)/", []string{"website", "legacy"}),
+		Entry("by regular expression containing commas", "name=/^[a-z]{6,7}$/,archived=false", []string{"website"}),
+		Entry("by flags", "archived=true,fork=true", []string{"legacy"}),
+		Entry("by negated flags", "private!=true, template != true,archived=false", []string{"api-users", "website"}),
+		Entry("by language ignoring case", "language=go", []string{"api-users", "api-billing"}),
+		Entry("by topic", "topic=team-web", []string{"api-users", "website"}),
+		Entry("by negated topic", "topic!=team-web,archived=false", []string{"api-billing", "template-go"}),
+		Entry("by days since last push", "pushed_before=180d", []string{"api-billing", "website", "legacy"}),
+		Entry("by date of last push", "pushed_before=2016-01-01", []string{"legacy"}),
+	)
+
+	DescribeTable("refuses invalid selectors",
+		func(selector string) {
+			_, err := ParseRepositorySelector(selector)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("without value", "archived"),
+		Entry("with unknown key", "owner=me"),
+		Entry("with invalid flag", "archived=maybe"),
+		Entry("with invalid date", "pushed_before=yesterday"),
+		Entry("with invalid glob", "name=[api"),
+		Entry("with invalid regular expression", "name=/(api/"),
+	)
+
+	It("combines filters", func() {
+		name, err := WithName("*-*")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(FilterRepositories(repositories, name, WithTopic("team-web")))).To(Equal([]string{"api-users"}))
+		Expect(names(FilterRepositories(repositories, WithPushedBefore(now.AddDate(0, 0, -300))))).To(Equal([]string{"api-billing", "legacy"}))
+	})
+})
@@ -2,24 +2,70 @@ package github
 
 import (
 	"context"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"time"
 
 	gh3 "github.com/google/go-github/v32/github"
+	"golang.org/x/crypto/ssh"
 )
 
 type PublicKey struct {
-	ID    *int64  `json:"id,omitempty"`
-	Key   *string `json:"key,omitempty"`
-	URL   *string `json:"url,omitempty"`
-	Title *string `json:"title,omitempty"`
+	ID          *int64     `json:"id,omitempty"`
+	Key         *string    `json:"key,omitempty"`
+	URL         *string    `json:"url,omitempty"`
+	Title       *string    `json:"title,omitempty"`
+	Type        *string    `json:"type,omitempty"`
+	Bits        *int       `json:"bits,omitempty"`
+	Fingerprint *string    `json:"fingerprint,omitempty"`
+	ReadOnly    *bool      `json:"read_only,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	LastUsed    *time.Time `json:"last_used,omitempty"`
 }
 
 func fromGh3Key(key *gh3.Key) PublicKey {
-	return PublicKey{
+	result := PublicKey{
 		ID:    key.ID,
 		Key:   key.Key,
 		URL:   key.URL,
 		Title: key.Title,
 	}
+	result.parse()
+	return result
+}
+
+// parse determines type, size and fingerprint of the key. Keys that can not be parsed are left as they are.
+func (s *PublicKey) parse() {
+	if s.Key == nil {
+		return
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(*s.Key))
+	if err != nil {
+		return
+	}
+	keyType := key.Type()
+	fingerprint := ssh.FingerprintSHA256(key)
+	s.Type = &keyType
+	s.Fingerprint = &fingerprint
+	if crypto, ok := key.(ssh.CryptoPublicKey); ok {
+		var bits int
+		switch k := crypto.CryptoPublicKey().(type) {
+		case *rsa.PublicKey:
+			bits = k.N.BitLen()
+		case *dsa.PublicKey:
+			bits = k.P.BitLen()
+		case *ecdsa.PublicKey:
+			bits = k.Curve.Params().BitSize
+		case ed25519.PublicKey:
+			bits = 256
+		}
+		if bits > 0 {
+			s.Bits = &bits
+		}
+	}
 }
 
 func (s *GithubClient) ListPublicKeys(user string) ([]PublicKey, error) {
@@ -33,3 +79,30 @@ func (s *GithubClient) ListPublicKeys(user string) ([]PublicKey, error) {
 	})
 	return result, err
 }
+
+func (s *GithubClient) LoadRepositoryDeployKeys(repositories ...*Repository) error {
+	for _, repository := range repositories {
+		if err := s.loadRepositoryDeployKeys(repository); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GithubClient) loadRepositoryDeployKeys(repository *Repository) error {
+	repository.DeployKeys = nil
+	// go-github does not know about last_used yet
+	return s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		var keys []PublicKey
+		req, err := s.v3Client.NewRequest("GET", fmt.Sprintf("repos/%v/%v/keys?per_page=%d&page=%d", repository.Owner, repository.Name, lo.PerPage, lo.Page), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := s.v3Client.Do(context.Background(), req, &keys)
+		for _, key := range keys {
+			key.parse()
+			repository.DeployKeys = append(repository.DeployKeys, key)
+		}
+		return resp, err
+	})
+}
@@ -0,0 +1,84 @@
+package github_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("PublicKey", func() {
+	var server *httptest.Server
+	var keys []map[string]interface{}
+
+	BeforeEach(func() {
+		keys = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/users/alice/keys"))
+			Expect(json.NewEncoder(w).Encode(keys)).To(Succeed())
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	listKey := func() PublicKey {
+		result, err := NewTestClient(server.URL).ListPublicKeys("alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(HaveLen(1))
+		return result[0]
+	}
+
+	DescribeTable("determines type, size and fingerprint",
+		func(generate func() crypto.PublicKey, keyType string, bits int) {
+			public, err := ssh.NewPublicKey(generate())
+			Expect(err).NotTo(HaveOccurred())
+			keys = []map[string]interface{}{{"id": 1, "key": string(ssh.MarshalAuthorizedKey(public))}}
+			key := listKey()
+			Expect(key.Type).NotTo(BeNil())
+			Expect(key.Bits).NotTo(BeNil())
+			Expect(key.Fingerprint).NotTo(BeNil())
+			Expect(*key.Type).To(Equal(keyType))
+			Expect(*key.Bits).To(Equal(bits))
+			Expect(*key.Fingerprint).To(Equal(ssh.FingerprintSHA256(public)))
+		},
+		Entry("of RSA keys", func() crypto.PublicKey {
+			key, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).NotTo(HaveOccurred())
+			return &key.PublicKey
+		}, "ssh-rsa", 1024),
+		Entry("of ECDSA keys", func() crypto.PublicKey {
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			return &key.PublicKey
+		}, "ecdsa-sha2-nistp384", 384),
+		Entry("of Ed25519 keys", func() crypto.PublicKey {
+			key, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			return key
+		}, "ssh-ed25519", 256),
+	)
+
+	DescribeTable("leaves keys it can not parse as they are",
+		func(key map[string]interface{}, expected PublicKey) {
+			keys = []map[string]interface{}{key}
+			Expect(listKey()).To(Equal(expected))
+		},
+		Entry("without key", map[string]interface{}{"id": 1}, PublicKey{ID: id(1)}),
+		Entry("with invalid key", map[string]interface{}{"id": 1, "key": "ssh-rsa not-base64"},
+			PublicKey{ID: id(1), Key: str("ssh-rsa not-base64")}),
+	)
+})
@@ -0,0 +1,293 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/mtrense/soil/logging"
+	"github.com/shurcooL/githubv4"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+const (
+	ActivityActive    = "active"
+	ActivityDormant   = "dormant"
+	ActivityAbandoned = "abandoned"
+)
+
+// StalenessThresholds define after how many days without activity a repository is considered dormant or abandoned.
+type StalenessThresholds struct {
+	DormantAfter   int `json:"dormant_after"`
+	AbandonedAfter int `json:"abandoned_after"`
+}
+
+type RepositoryActivity struct {
+	Repository       string    `json:"repository,omitempty"`
+	PushedAt         time.Time `json:"pushed_at,omitempty"`
+	LastWorkflowRun  time.Time `json:"last_workflow_run,omitempty"`
+	OpenPullRequests int       `json:"open_pull_requests"`
+	OpenIssues       int       `json:"open_issues"`
+	// LastDiscussion is the most recent update of an open pull request or issue.
+	LastDiscussion time.Time `json:"last_discussion,omitempty"`
+	// Dependents are the repositories of the organization depending on this one according to the dependency graph.
+	Dependents     []string  `json:"dependents,omitempty"`
+	LastActivity   time.Time `json:"last_activity,omitempty"`
+	InactiveDays   int       `json:"inactive_days"`
+	Classification string    `json:"classification,omitempty"`
+	repository     *Repository
+}
+
+type StaleRepositoryAudit struct {
+	Thresholds   StalenessThresholds   `json:"thresholds"`
+	Repositories []*RepositoryActivity `json:"repositories,omitempty"`
+}
+
+// Classify determines the classification from the last activity. Repositories other repositories depend on are
+// never considered abandoned.
+func (s *RepositoryActivity) Classify(thresholds StalenessThresholds, now time.Time) {
+	s.LastActivity = s.PushedAt
+	for _, t := range []time.Time{s.LastWorkflowRun, s.LastDiscussion} {
+		if t.After(s.LastActivity) {
+			s.LastActivity = t
+		}
+	}
+	s.InactiveDays = int(now.Sub(s.LastActivity).Hours() / 24)
+	switch {
+	case s.InactiveDays < thresholds.DormantAfter:
+		s.Classification = ActivityActive
+	case s.InactiveDays < thresholds.AbandonedAfter || len(s.Dependents) > 0:
+		s.Classification = ActivityDormant
+	default:
+		s.Classification = ActivityAbandoned
+	}
+}
+
+// StaleRepositoryAudit classifies the non-archived repositories of the organization by their last push, open pull
+// requests and issues, workflow runs and dependents within the organization. Dependencies are looked up for all
+// repositories, while the activity is only loaded for the repositories matching the filters.
+func (s *GithubClient) StaleRepositoryAudit(org string, thresholds StalenessThresholds, filters ...RepositoryFilter) (*StaleRepositoryAudit, error) {
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return nil, err
+	}
+	var active []*Repository
+	for _, r := range repositories {
+		if !r.Archived {
+			active = append(active, r)
+		}
+	}
+	dependents := make(map[string][]string)
+	for _, r := range active {
+		dependencies, err := s.loadRepositoryDependencies(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range dependencies {
+			if !strings.EqualFold(d, r.Owner+"/"+r.Name) {
+				dependents[strings.ToLower(d)] = append(dependents[strings.ToLower(d)], r.Name)
+			}
+		}
+	}
+	audit := &StaleRepositoryAudit{Thresholds: thresholds}
+	now := time.Now()
+	for _, r := range FilterRepositories(active, filters...) {
+		activity, err := s.loadRepositoryActivity(r)
+		if err != nil {
+			return nil, err
+		}
+		activity.Dependents = dependents[strings.ToLower(r.Owner+"/"+r.Name)]
+		sort.Strings(activity.Dependents)
+		activity.Classify(thresholds, now)
+		audit.Repositories = append(audit.Repositories, activity)
+	}
+	return audit, nil
+}
+
+// loadRepositoryActivity returns the activity of the repository. The notice opened by StaleRepositoryNotificationPlan
+// does not count as activity, otherwise announcing the archival would keep the repository from being archived.
+func (s *GithubClient) loadRepositoryActivity(repository *Repository) (*RepositoryActivity, error) {
+	var query struct {
+		Repository struct {
+			PullRequests struct {
+				TotalCount githubv4.Int
+				Nodes      []struct {
+					UpdatedAt githubv4.DateTime
+				}
+			} `graphql:"pullRequests(states: OPEN, first: 1, orderBy: {field: UPDATED_AT, direction: DESC})"`
+			Issues struct {
+				TotalCount githubv4.Int
+				Nodes      []struct {
+					Title     githubv4.String
+					UpdatedAt githubv4.DateTime
+				}
+			} `graphql:"issues(states: OPEN, first: 2, orderBy: {field: UPDATED_AT, direction: DESC})"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := s.Query(&query).Str("owner", repository.Owner).Str("repo", repository.Name).Run(); err != nil {
+		return nil, err
+	}
+	activity := &RepositoryActivity{
+		Repository:       repository.Name,
+		PushedAt:         repository.PushedAt,
+		OpenPullRequests: int(query.Repository.PullRequests.TotalCount),
+		OpenIssues:       int(query.Repository.Issues.TotalCount),
+		repository:       repository,
+	}
+	for _, n := range query.Repository.PullRequests.Nodes {
+		activity.LastDiscussion = n.UpdatedAt.Time
+	}
+	for _, n := range query.Repository.Issues.Nodes {
+		if n.Title == staleRepositoryNoticeTitle {
+			activity.OpenIssues--
+			continue
+		}
+		if n.UpdatedAt.After(activity.LastDiscussion) {
+			activity.LastDiscussion = n.UpdatedAt.Time
+		}
+		break
+	}
+	runs, _, err := s.v3Client.Actions.ListRepositoryWorkflowRuns(context.Background(), repository.Owner, repository.Name, &gh3.ListWorkflowRunsOptions{
+		ListOptions: gh3.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, run := range runs.WorkflowRuns {
+		activity.LastWorkflowRun = run.GetCreatedAt().Time
+	}
+	return activity, nil
+}
+
+// loadRepositoryDependencies returns the full names of the repositories the repository depends on according to the
+// dependency graph, which is only available as schema preview.
+func (s *GithubClient) loadRepositoryDependencies(repository *Repository) ([]string, error) {
+	var query struct {
+		Repository struct {
+			DependencyGraphManifests struct {
+				Nodes []struct {
+					Dependencies struct {
+						Nodes []struct {
+							Repository struct {
+								NameWithOwner githubv4.String
+							}
+						}
+					} `graphql:"dependencies(first: 100)"`
+				}
+			} `graphql:"dependencyGraphManifests(first: 20, withDependencies: true)"`
+		} `graphql:"repository(owner: $owner, name: $repo)"`
+	}
+	if err := s.Query(&query).Str("owner", repository.Owner).Str("repo", repository.Name).Preview().Run(); err != nil {
+		return nil, err
+	}
+	var dependencies []string
+	for _, m := range query.Repository.DependencyGraphManifests.Nodes {
+		for _, d := range m.Dependencies.Nodes {
+			if d.Repository.NameWithOwner != "" {
+				dependencies = append(dependencies, string(d.Repository.NameWithOwner))
+			}
+		}
+	}
+	return dependencies, nil
+}
+
+const staleRepositoryNoticeTitle = "Archiving abandoned repository"
+
+// staleRepositoryNotice returns the open notice issue of the repository, nil if there is none.
+func (s *GithubClient) staleRepositoryNotice(repository *Repository) (*gh3.Issue, error) {
+	var notice *gh3.Issue
+	err := s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		issues, resp, err := s.v3Client.Issues.ListByRepo(context.Background(), repository.Owner, repository.Name, &gh3.IssueListByRepoOptions{
+			State:       "open",
+			ListOptions: lo,
+		})
+		for _, issue := range issues {
+			if notice == nil && issue.GetTitle() == staleRepositoryNoticeTitle && !issue.IsPullRequest() {
+				notice = issue
+			}
+		}
+		return resp, err
+	})
+	return notice, err
+}
+
+// StaleRepositoryNotificationPlan plans to open an issue, mentioning their admins, in all abandoned repositories of
+// the audit that have none open yet. Repositories with issues disabled are skipped, they can not be notified.
+func (s *GithubClient) StaleRepositoryNotificationPlan(audit *StaleRepositoryAudit) (*Plan, error) {
+	plan := &Plan{}
+	for _, activity := range audit.Repositories {
+		if activity.Classification != ActivityAbandoned {
+			continue
+		}
+		repository := activity.repository
+		if !repository.IssuesEnabled {
+			log.L().Warn().Str("repository", repository.Name).Msg("Issues are disabled, can not announce archival")
+			continue
+		}
+		if notice, err := s.staleRepositoryNotice(repository); err != nil {
+			return nil, err
+		} else if notice != nil {
+			continue
+		}
+		if err := s.LoadRepositoryCollaborators(repository); err != nil {
+			return nil, err
+		}
+		var admins []string
+		for _, c := range repository.Collaborators {
+			if permissionRank(c.EffectivePermission) == permissionRank("ADMIN") {
+				admins = append(admins, "@"+c.Login)
+			}
+		}
+		body := fmt.Sprintf("This repository had no activity for %d days (last activity on %s) and is going to be archived.",
+			activity.InactiveDays, activity.LastActivity.Format("2006-01-02"))
+		if len(admins) > 0 {
+			body += "\n\ncc " + strings.Join(admins, " ")
+		}
+		plan.Add("open-issue", repository.Name, staleRepositoryNoticeTitle, fmt.Sprintf("no activity for %d days", activity.InactiveDays), func() error {
+			_, _, err := s.v3Client.Issues.Create(context.Background(), repository.Owner, repository.Name, &gh3.IssueRequest{
+				Title: boxString(staleRepositoryNoticeTitle),
+				Body:  &body,
+			})
+			return err
+		})
+	}
+	return plan, nil
+}
+
+// StaleRepositoryArchivalPlan plans the archival of the abandoned repositories of the audit that were notified by
+// StaleRepositoryNotificationPlan at least gracePeriod ago. Repositories with issues disabled can not be notified and
+// are skipped.
+func (s *GithubClient) StaleRepositoryArchivalPlan(audit *StaleRepositoryAudit, gracePeriod time.Duration) (*Plan, error) {
+	plan := &Plan{}
+	for _, activity := range audit.Repositories {
+		if activity.Classification != ActivityAbandoned {
+			continue
+		}
+		repository := activity.repository
+		if !repository.IssuesEnabled {
+			log.L().Warn().Str("repository", repository.Name).Msg("Issues are disabled, archival can not be announced")
+			continue
+		}
+		notice, err := s.staleRepositoryNotice(repository)
+		if err != nil {
+			return nil, err
+		}
+		if notice == nil {
+			log.L().Info().Str("repository", repository.Name).Msg("Archival has not been announced yet")
+			continue
+		}
+		if time.Since(notice.GetCreatedAt()) < gracePeriod {
+			continue
+		}
+		reason := fmt.Sprintf("no activity for %d days, announced in #%d on %s", activity.InactiveDays, notice.GetNumber(),
+			notice.GetCreatedAt().Format("2006-01-02"))
+		plan.Add("archive", repository.Name, "", reason, func() error {
+			_, err := s.ArchiveRepository(repository)
+			return err
+		})
+	}
+	return plan, nil
+}
@@ -0,0 +1,34 @@
+package github_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("RepositoryActivity", func() {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.AddDate(0, 0, -days)
+	}
+	thresholds := StalenessThresholds{DormantAfter: 90, AbandonedAfter: 365}
+
+	DescribeTable("classifies repositories by their last activity",
+		func(activity RepositoryActivity, lastActivity time.Time, inactiveDays int, classification string) {
+			activity.Classify(thresholds, now)
+			Expect(activity.LastActivity).To(Equal(lastActivity))
+			Expect(activity.InactiveDays).To(Equal(inactiveDays))
+			Expect(activity.Classification).To(Equal(classification))
+		},
+		Entry("active after a recent push", RepositoryActivity{PushedAt: daysAgo(10)}, daysAgo(10), 10, ActivityActive),
+		Entry("dormant after the dormant threshold", RepositoryActivity{PushedAt: daysAgo(90)}, daysAgo(90), 90, ActivityDormant),
+		Entry("abandoned after the abandoned threshold", RepositoryActivity{PushedAt: daysAgo(365)}, daysAgo(365), 365, ActivityAbandoned),
+		Entry("active after a recent workflow run", RepositoryActivity{PushedAt: daysAgo(400), LastWorkflowRun: daysAgo(5)}, daysAgo(5), 5, ActivityActive),
+		Entry("dormant after a discussion", RepositoryActivity{PushedAt: daysAgo(400), LastDiscussion: daysAgo(100)}, daysAgo(100), 100, ActivityDormant),
+		Entry("dormant instead of abandoned with dependents", RepositoryActivity{PushedAt: daysAgo(500), Dependents: []string{"api"}}, daysAgo(500), 500, ActivityDormant),
+	)
+})
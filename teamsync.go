@@ -0,0 +1,157 @@
+package github
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DesiredTeams maps team slugs to the members the team should have.
+type DesiredTeams map[string]DesiredTeam
+
+type DesiredTeam struct {
+	Maintainers []string `json:"maintainers,omitempty" yaml:"maintainers,omitempty"`
+	Members     []string `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// ReadDesiredTeamsYAML reads the desired team membership from a YAML document of the form:
+//
+//	platform:
+//	  maintainers: [alice]
+//	  members: [bob, carol]
+func ReadDesiredTeamsYAML(r io.Reader) (DesiredTeams, error) {
+	var teams DesiredTeams
+	err := yaml.NewDecoder(r).Decode(&teams)
+	return teams, err
+}
+
+// ReadDesiredTeamsCSV reads the desired team membership from CSV rows of the form "team,login,role" where the role
+// is optional and defaults to member. A leading header row is skipped.
+func ReadDesiredTeamsCSV(r io.Reader) (DesiredTeams, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	teams := make(DesiredTeams)
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "team") {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected at least team and login", i+1)
+		}
+		team := teams[record[0]]
+		if len(record) > 2 && strings.EqualFold(record[2], TeamRoleMaintainer) {
+			team.Maintainers = append(team.Maintainers, record[1])
+		} else {
+			team.Members = append(team.Members, record[1])
+		}
+		teams[record[0]] = team
+	}
+	return teams, nil
+}
+
+func (s DesiredTeam) roles() map[string]string {
+	roles := make(map[string]string)
+	for _, login := range s.Members {
+		roles[strings.ToLower(login)] = TeamRoleMember
+	}
+	for _, login := range s.Maintainers {
+		roles[strings.ToLower(login)] = TeamRoleMaintainer
+	}
+	return roles
+}
+
+// TeamSyncPlan computes the changes needed to bring the membership of the given teams in line with the desired
+// state. Teams not mentioned in desired are left untouched. The plan is refused if a team with maintainers would be left
+// without any desired maintainer, or if it contains more than maxChanges actions (unless maxChanges is 0). In the
+// latter case the plan is returned along with the error, so it can still be reviewed.
+func (s *GithubClient) TeamSyncPlan(org string, desired DesiredTeams, maxChanges int) (*Plan, error) {
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	bySlug := make(map[string]*Team)
+	for _, team := range teams {
+		bySlug[team.Slug] = team
+	}
+	var slugs []string
+	for slug := range desired {
+		if _, ok := bySlug[slug]; !ok {
+			return nil, fmt.Errorf("team %s does not exist in organization %s", slug, org)
+		}
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	plan := &Plan{}
+	for _, slug := range slugs {
+		team := bySlug[slug]
+		if err := s.LoadImmediateTeamMembers(org, team); err != nil {
+			return nil, err
+		}
+		if err := s.planTeamSync(org, team, desired[slug], plan); err != nil {
+			return nil, err
+		}
+	}
+	if maxChanges > 0 && len(plan.Actions) > maxChanges {
+		return plan, fmt.Errorf("plan contains %d changes, which exceeds the maximum of %d", len(plan.Actions), maxChanges)
+	}
+	return plan, nil
+}
+
+func (s *GithubClient) planTeamSync(org string, team *Team, desired DesiredTeam, plan *Plan) error {
+	wanted := desired.roles()
+	current := make(map[string]string)
+	hasMaintainers := false
+	for _, m := range team.Members {
+		current[strings.ToLower(m.Login)] = m.Role
+		hasMaintainers = hasMaintainers || m.Role == TeamRoleMaintainer
+	}
+	if hasMaintainers && len(desired.Maintainers) == 0 {
+		return fmt.Errorf("refusing to leave team %s without maintainers", team.Slug)
+	}
+	slug := team.Slug
+	var logins []string
+	for login := range wanted {
+		logins = append(logins, login)
+	}
+	for login := range current {
+		if _, ok := wanted[login]; !ok {
+			logins = append(logins, login)
+		}
+	}
+	sort.Strings(logins)
+	// Removals are planned last, so that a team never drops below its desired maintainers while the plan is executed
+	var removals []string
+	for _, login := range logins {
+		login := login
+		wantedRole, isWanted := wanted[login]
+		currentRole, isCurrent := current[login]
+		switch {
+		case !isWanted:
+			removals = append(removals, login)
+		case !isCurrent:
+			plan.Add("add-team-member", slug, login+" as "+strings.ToLower(wantedRole), "", func() error {
+				return s.AddTeamMember(org, slug, login, wantedRole)
+			})
+		case currentRole != wantedRole:
+			plan.Add("change-team-role", slug, login+" to "+strings.ToLower(wantedRole), "", func() error {
+				return s.AddTeamMember(org, slug, login, wantedRole)
+			})
+		}
+	}
+	for _, login := range removals {
+		login := login
+		plan.Add("remove-team-member", slug, login, "", func() error {
+			return s.RemoveTeamMember(org, slug, login)
+		})
+	}
+	return nil
+}
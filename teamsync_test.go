@@ -0,0 +1,128 @@
+package github_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("Team sync", func() {
+	DescribeTable("reads the desired teams from CSV",
+		func(content string, expected DesiredTeams) {
+			teams, err := ReadDesiredTeamsCSV(strings.NewReader(content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(teams).To(Equal(expected))
+		},
+		Entry("with roles", "platform,alice,maintainer\nplatform,bob,member\n", DesiredTeams{
+			"platform": {Maintainers: []string{"alice"}, Members: []string{"bob"}},
+		}),
+		Entry("skipping the header", "team,login,role\nplatform,alice,MAINTAINER\n", DesiredTeams{
+			"platform": {Maintainers: []string{"alice"}},
+		}),
+		Entry("defaulting to member", "platform, alice\nweb,bob,\n", DesiredTeams{
+			"platform": {Members: []string{"alice"}},
+			"web":      {Members: []string{"bob"}},
+		}),
+	)
+
+	It("refuses CSV rows without login", func() {
+		_, err := ReadDesiredTeamsCSV(strings.NewReader("platform,alice\nweb\n"))
+		Expect(err).To(MatchError("line 2: expected at least team and login"))
+	})
+
+	Describe("TeamSyncPlan", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request struct {
+					Query string
+				}
+				Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				if strings.Contains(request.Query, "members(") {
+					w.Write([]byte(`{"data": {"organization": {"team": {"members": {
+						"pageInfo": {"hasNextPage": false},
+						"edges": [
+							{"node": {"login": "alice"}, "role": "MAINTAINER"},
+							{"node": {"login": "Bob"}, "role": "MEMBER"},
+							{"node": {"login": "carol"}, "role": "MEMBER"}
+						]
+					}}}}}`))
+					return
+				}
+				w.Write([]byte(`{"data": {"organization": {"teams": {
+					"pageInfo": {"hasNextPage": false},
+					"nodes": [{"id": "T_1", "name": "Platform", "slug": "platform"}]
+				}}}}`))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		type action struct {
+			Operation string
+			Detail    string
+		}
+
+		DescribeTable("plans the membership changes",
+			func(desired DesiredTeam, expected []action) {
+				plan, err := NewTestClient(server.URL).TeamSyncPlan("org", DesiredTeams{"platform": desired}, 0)
+				Expect(err).NotTo(HaveOccurred())
+				var actions []action
+				for _, a := range plan.Actions {
+					Expect(a.Target).To(Equal("platform"))
+					actions = append(actions, action{a.Operation, a.Detail})
+				}
+				Expect(actions).To(Equal(expected))
+			},
+			Entry("nothing if the team is in sync", DesiredTeam{
+				Maintainers: []string{"alice"},
+				Members:     []string{"bob", "carol"},
+			}, nil),
+			Entry("removals after additions and role changes", DesiredTeam{
+				Maintainers: []string{"alice", "carol"},
+				Members:     []string{"dave"},
+			}, []action{
+				{"change-team-role", "carol to maintainer"},
+				{"add-team-member", "dave as member"},
+				{"remove-team-member", "bob"},
+			}),
+			Entry("a handover to another maintainer", DesiredTeam{
+				Maintainers: []string{"carol"},
+				Members:     []string{"bob"},
+			}, []action{
+				{"change-team-role", "carol to maintainer"},
+				{"remove-team-member", "alice"},
+			}),
+		)
+
+		It("refuses to leave a team without maintainers", func() {
+			_, err := NewTestClient(server.URL).TeamSyncPlan("org", DesiredTeams{
+				"platform": {Members: []string{"alice", "bob", "carol"}},
+			}, 0)
+			Expect(err).To(MatchError("refusing to leave team platform without maintainers"))
+		})
+
+		It("refuses unknown teams", func() {
+			_, err := NewTestClient(server.URL).TeamSyncPlan("org", DesiredTeams{"web": {}}, 0)
+			Expect(err).To(MatchError("team web does not exist in organization org"))
+		})
+
+		It("returns the plan along with the error when exceeding the maximum of changes", func() {
+			plan, err := NewTestClient(server.URL).TeamSyncPlan("org", DesiredTeams{
+				"platform": {Maintainers: []string{"alice"}, Members: []string{"dave"}},
+			}, 2)
+			Expect(err).To(MatchError("plan contains 3 changes, which exceeds the maximum of 2"))
+			Expect(plan.Actions).To(HaveLen(3))
+		})
+	})
+})
@@ -0,0 +1,167 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TeamNode is a Team placed in the team hierarchy of its organization. Following the semantics of Github, repository
+// permissions are inherited from parent teams downwards, while members of child teams are also members of all their
+// parent teams.
+type TeamNode struct {
+	*Team
+	ParentTeam            *TeamNode                  `json:"-"`
+	Children              []*TeamNode                `json:"children,omitempty"`
+	InheritedMembers      []*InheritedTeamMember     `json:"inherited_members,omitempty"`
+	InheritedRepositories []*InheritedTeamRepository `json:"inherited_repositories,omitempty"`
+}
+
+type InheritedTeamMember struct {
+	Login string `json:"login,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Via   string `json:"via,omitempty"`
+}
+
+type InheritedTeamRepository struct {
+	Owner      string `json:"owner,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Permission string `json:"permission,omitempty"`
+	Via        string `json:"via,omitempty"`
+}
+
+// GetTeamTree fetches all teams of the organization together with their members and repositories and returns the root
+// teams of the resulting hierarchy.
+func (s *GithubClient) GetTeamTree(org string) ([]*TeamNode, error) {
+	teams, err := s.GetTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.LoadImmediateTeamMembers(org, teams...); err != nil {
+		return nil, err
+	}
+	if err := s.LoadTeamRepositories(org, teams...); err != nil {
+		return nil, err
+	}
+	return BuildTeamTree(teams), nil
+}
+
+// BuildTeamTree arranges the given teams into their hierarchy and returns the root teams. Inherited members and
+// repositories are only computed from what has been loaded into the teams beforehand.
+func BuildTeamTree(teams []*Team) []*TeamNode {
+	nodes := make(map[string]*TeamNode)
+	for _, team := range teams {
+		nodes[team.ID] = &TeamNode{Team: team}
+	}
+	var roots []*TeamNode
+	for _, team := range teams {
+		node := nodes[team.ID]
+		if parent, ok := nodes[team.Parent]; ok {
+			node.ParentTeam = parent
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	sortTeamNodes(roots)
+	for _, node := range nodes {
+		sortTeamNodes(node.Children)
+		node.inheritRepositories()
+		node.inheritMembers()
+	}
+	return roots
+}
+
+func sortTeamNodes(nodes []*TeamNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// Walk calls fn for the node and all its descendants, parents before their children.
+func (s *TeamNode) Walk(fn func(node *TeamNode, depth int)) {
+	s.walk(fn, 0)
+}
+
+func (s *TeamNode) walk(fn func(node *TeamNode, depth int), depth int) {
+	fn(s, depth)
+	for _, child := range s.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+func (s *TeamNode) inheritRepositories() {
+	direct := make(map[string]int)
+	for _, r := range s.Repositories {
+		direct[r.Owner+"/"+r.Name] = permissionRank(r.Permission)
+	}
+	inherited := make(map[string]*InheritedTeamRepository)
+	for ancestor := s.ParentTeam; ancestor != nil; ancestor = ancestor.ParentTeam {
+		for _, r := range ancestor.Repositories {
+			key := r.Owner + "/" + r.Name
+			rank := permissionRank(r.Permission)
+			if rank <= direct[key] {
+				continue
+			}
+			if existing, ok := inherited[key]; ok && rank <= permissionRank(existing.Permission) {
+				continue
+			}
+			inherited[key] = &InheritedTeamRepository{
+				Owner:      r.Owner,
+				Name:       r.Name,
+				Permission: r.Permission,
+				Via:        ancestor.Slug,
+			}
+		}
+	}
+	s.InheritedRepositories = nil
+	for _, r := range inherited {
+		s.InheritedRepositories = append(s.InheritedRepositories, r)
+	}
+	sort.Slice(s.InheritedRepositories, func(i, j int) bool {
+		return s.InheritedRepositories[i].Name < s.InheritedRepositories[j].Name
+	})
+}
+
+func (s *TeamNode) inheritMembers() {
+	seen := make(map[string]bool)
+	for _, m := range s.Members {
+		seen[m.Login] = true
+	}
+	s.InheritedMembers = nil
+	for _, child := range s.Children {
+		child.Walk(func(descendant *TeamNode, depth int) {
+			for _, m := range descendant.Members {
+				if seen[m.Login] {
+					continue
+				}
+				seen[m.Login] = true
+				s.InheritedMembers = append(s.InheritedMembers, &InheritedTeamMember{
+					Login: m.Login,
+					Role:  TeamRoleMember,
+					Via:   descendant.Slug,
+				})
+			}
+		})
+	}
+}
+
+// FormatTeamTree renders the given team hierarchy as an ASCII tree.
+func FormatTeamTree(roots []*TeamNode) string {
+	var sb strings.Builder
+	for _, root := range roots {
+		formatTeamNode(&sb, root, "", "", "")
+	}
+	return sb.String()
+}
+
+func formatTeamNode(sb *strings.Builder, node *TeamNode, prefix, branch, indent string) {
+	sb.WriteString(fmt.Sprintf("%s%s%s (%d members, %d repositories)\n", prefix, branch, node.Slug, node.MemberCount, node.RepositoryCount))
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			formatTeamNode(sb, child, prefix+indent, "└── ", "    ")
+		} else {
+			formatTeamNode(sb, child, prefix+indent, "├── ", "│   ")
+		}
+	}
+}
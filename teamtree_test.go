@@ -0,0 +1,89 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("BuildTeamTree", func() {
+	// platform has the children backend and frontend, backend has the child databases
+	teams := func() []*Team {
+		return []*Team{
+			{ID: "2", Slug: "backend", Name: "backend", Parent: "1",
+				Members:      []*TeamMember{{Login: "bob", Role: TeamRoleMaintainer}, {Login: "carol", Role: TeamRoleMember}},
+				Repositories: []*TeamRepository{{Owner: "org", Name: "api", Permission: "WRITE"}},
+			},
+			{ID: "1", Slug: "platform", Name: "platform",
+				Members: []*TeamMember{{Login: "alice", Role: TeamRoleMaintainer}},
+				Repositories: []*TeamRepository{
+					{Owner: "org", Name: "api", Permission: "READ"},
+					{Owner: "org", Name: "docs", Permission: "MAINTAIN"},
+				},
+			},
+			{ID: "4", Slug: "databases", Name: "databases", Parent: "2",
+				Members:      []*TeamMember{{Login: "dave", Role: TeamRoleMaintainer}, {Login: "bob", Role: TeamRoleMember}},
+				Repositories: []*TeamRepository{{Owner: "org", Name: "docs", Permission: "ADMIN"}},
+			},
+			{ID: "3", Slug: "frontend", Name: "frontend", Parent: "1"},
+		}
+	}
+	nodes := func() map[string]*TeamNode {
+		result := make(map[string]*TeamNode)
+		for _, root := range BuildTeamTree(teams()) {
+			root.Walk(func(node *TeamNode, depth int) {
+				result[node.Slug] = node
+			})
+		}
+		return result
+	}
+
+	It("arranges teams by their parents", func() {
+		roots := BuildTeamTree(teams())
+		Expect(roots).To(HaveLen(1))
+		Expect(roots[0].Slug).To(Equal("platform"))
+		var walked []string
+		roots[0].Walk(func(node *TeamNode, depth int) {
+			walked = append(walked, node.Slug)
+		})
+		Expect(walked).To(Equal([]string{"platform", "backend", "databases", "frontend"}))
+	})
+
+	DescribeTable("inherits members from child teams",
+		func(slug string, expected []InheritedTeamMember) {
+			var inherited []InheritedTeamMember
+			for _, m := range nodes()[slug].InheritedMembers {
+				inherited = append(inherited, *m)
+			}
+			Expect(inherited).To(Equal(expected))
+		},
+		Entry("from all descendants, once each", "platform", []InheritedTeamMember{
+			{Login: "bob", Role: TeamRoleMember, Via: "backend"},
+			{Login: "carol", Role: TeamRoleMember, Via: "backend"},
+			{Login: "dave", Role: TeamRoleMember, Via: "databases"},
+		}),
+		Entry("except direct members", "backend", []InheritedTeamMember{
+			{Login: "dave", Role: TeamRoleMember, Via: "databases"},
+		}),
+		Entry("nothing without children", "databases", nil),
+	)
+
+	DescribeTable("inherits repositories from parent teams",
+		func(slug string, expected []InheritedTeamRepository) {
+			var inherited []InheritedTeamRepository
+			for _, r := range nodes()[slug].InheritedRepositories {
+				inherited = append(inherited, *r)
+			}
+			Expect(inherited).To(Equal(expected))
+		},
+		Entry("only if they grant more than direct access", "backend", []InheritedTeamRepository{
+			{Owner: "org", Name: "docs", Permission: "MAINTAIN", Via: "platform"},
+		}),
+		Entry("with the highest permission of all ancestors", "databases", []InheritedTeamRepository{
+			{Owner: "org", Name: "api", Permission: "WRITE", Via: "backend"},
+		}),
+		Entry("nothing for root teams", "platform", nil),
+	)
+})
@@ -0,0 +1,282 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	gh3 "github.com/google/go-github/v32/github"
+)
+
+type Webhook struct {
+	ID int64 `json:"id,omitempty"`
+	// Repository is empty for webhooks of the organization.
+	Repository         string    `json:"repository,omitempty"`
+	URL                string    `json:"url,omitempty"`
+	Host               string    `json:"host,omitempty"`
+	Events             []string  `json:"events,omitempty"`
+	ContentType        string    `json:"content_type,omitempty"`
+	InsecureSSL        bool      `json:"insecure_ssl,omitempty"`
+	Active             bool      `json:"active"`
+	LastDelivery       string    `json:"last_delivery,omitempty"`
+	LastDeliveryAt     time.Time `json:"last_delivery_at,omitempty"`
+	LastDeliveryFailed bool      `json:"last_delivery_failed,omitempty"`
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`
+}
+
+// WebhookSettings describe a webhook to create or the changes to an existing one, empty settings are left unchanged.
+type WebhookSettings struct {
+	URL         string   `json:"url,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+	Secret      string   `json:"secret,omitempty"`
+	InsecureSSL *bool    `json:"insecure_ssl,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+func (s WebhookSettings) config() map[string]interface{} {
+	config := make(map[string]interface{})
+	if s.URL != "" {
+		config["url"] = s.URL
+	}
+	if s.ContentType != "" {
+		config["content_type"] = s.ContentType
+	}
+	if s.Secret != "" {
+		config["secret"] = s.Secret
+	}
+	if s.InsecureSSL != nil {
+		config["insecure_ssl"] = "0"
+		if *s.InsecureSSL {
+			config["insecure_ssl"] = "1"
+		}
+	}
+	return config
+}
+
+func fromGh3Hook(repository string, h *gh3.Hook) *Webhook {
+	hook := &Webhook{
+		ID:         h.GetID(),
+		Repository: repository,
+		Events:     h.Events,
+		Active:     h.GetActive(),
+		CreatedAt:  h.GetCreatedAt(),
+		UpdatedAt:  h.GetUpdatedAt(),
+	}
+	hook.URL, _ = h.Config["url"].(string)
+	hook.ContentType, _ = h.Config["content_type"].(string)
+	// insecure_ssl is documented as string, but some hooks report it as number
+	hook.InsecureSSL = fmt.Sprint(h.Config["insecure_ssl"]) == "1"
+	if u, err := url.Parse(hook.URL); err == nil {
+		hook.Host = u.Hostname()
+	}
+	return hook
+}
+
+// hooksPath returns the API path of the webhooks of the repository or, if repository is empty, of the organization.
+func hooksPath(owner string, repository string) string {
+	if repository == "" {
+		return fmt.Sprintf("orgs/%v/hooks", owner)
+	}
+	return fmt.Sprintf("repos/%v/%v/hooks", owner, repository)
+}
+
+func (s *GithubClient) GetOrganizationWebhooks(org string) ([]*Webhook, error) {
+	return s.getWebhooks(org, "")
+}
+
+func (s *GithubClient) LoadRepositoryWebhooks(repositories ...*Repository) error {
+	for _, repository := range repositories {
+		if err := s.loadRepositoryWebhooks(repository); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *GithubClient) loadRepositoryWebhooks(repository *Repository) error {
+	hooks, err := s.getWebhooks(repository.Owner, repository.Name)
+	repository.Webhooks = hooks
+	return err
+}
+
+func (s *GithubClient) getWebhooks(owner string, repository string) ([]*Webhook, error) {
+	ctx := context.Background()
+	var webhooks []*Webhook
+	err := s.paginateGithub3(func(lo gh3.ListOptions) (*gh3.Response, error) {
+		var hooks []*gh3.Hook
+		var resp *gh3.Response
+		var err error
+		if repository == "" {
+			hooks, resp, err = s.v3Client.Organizations.ListHooks(ctx, owner, &lo)
+		} else {
+			hooks, resp, err = s.v3Client.Repositories.ListHooks(ctx, owner, repository, &lo)
+		}
+		for _, h := range hooks {
+			webhook := fromGh3Hook(repository, h)
+			if err := s.loadLastDelivery(owner, webhook); err != nil {
+				return nil, err
+			}
+			webhooks = append(webhooks, webhook)
+		}
+		return resp, err
+	})
+	return webhooks, err
+}
+
+func (s *GithubClient) loadLastDelivery(owner string, webhook *Webhook) error {
+	var deliveries []struct {
+		Status      string    `json:"status"`
+		StatusCode  int       `json:"status_code"`
+		DeliveredAt time.Time `json:"delivered_at"`
+	}
+	req, err := s.v3Client.NewRequest("GET", fmt.Sprintf("%s/%d/deliveries?per_page=1", hooksPath(owner, webhook.Repository), webhook.ID), nil)
+	if err != nil {
+		return err
+	}
+	if _, err := s.v3Client.Do(context.Background(), req, &deliveries); err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		webhook.LastDelivery = fmt.Sprintf("%d %s", d.StatusCode, d.Status)
+		webhook.LastDeliveryAt = d.DeliveredAt
+		webhook.LastDeliveryFailed = d.StatusCode < 200 || d.StatusCode >= 300
+	}
+	return nil
+}
+
+// CreateWebhook creates a webhook on the repository or, if repository is empty, on the organization. Webhooks are
+// active and deliver JSON unless settings say otherwise.
+func (s *GithubClient) CreateWebhook(owner string, repository string, settings WebhookSettings) (*Webhook, error) {
+	if settings.URL == "" {
+		return nil, fmt.Errorf("a webhook needs a url")
+	}
+	if settings.ContentType == "" {
+		settings.ContentType = "json"
+	}
+	active := settings.Active == nil || *settings.Active
+	hook := &gh3.Hook{
+		Config: settings.config(),
+		Events: settings.Events,
+		Active: &active,
+	}
+	var result *gh3.Hook
+	var err error
+	if repository == "" {
+		result, _, err = s.v3Client.Organizations.CreateHook(context.Background(), owner, hook)
+	} else {
+		result, _, err = s.v3Client.Repositories.CreateHook(context.Background(), owner, repository, hook)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromGh3Hook(repository, result), nil
+}
+
+// UpdateWebhook changes the given settings of the webhook. Configuration is updated separately from events and
+// active flag, so the secret of the webhook is kept unless a new one is given.
+func (s *GithubClient) UpdateWebhook(owner string, repository string, id int64, settings WebhookSettings) (*Webhook, error) {
+	ctx := context.Background()
+	if config := settings.config(); len(config) > 0 {
+		req, err := s.v3Client.NewRequest("PATCH", fmt.Sprintf("%s/%d/config", hooksPath(owner, repository), id), config)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.v3Client.Do(ctx, req, nil); err != nil {
+			return nil, err
+		}
+	}
+	hook := &gh3.Hook{
+		Events: settings.Events,
+		Active: settings.Active,
+	}
+	var result *gh3.Hook
+	var err error
+	if repository == "" {
+		result, _, err = s.v3Client.Organizations.EditHook(ctx, owner, id, hook)
+	} else {
+		result, _, err = s.v3Client.Repositories.EditHook(ctx, owner, repository, id, hook)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fromGh3Hook(repository, result), nil
+}
+
+func (s *GithubClient) DeleteWebhook(owner string, repository string, id int64) error {
+	var err error
+	if repository == "" {
+		_, err = s.v3Client.Organizations.DeleteHook(context.Background(), owner, id)
+	} else {
+		_, err = s.v3Client.Repositories.DeleteHook(context.Background(), owner, repository, id)
+	}
+	return err
+}
+
+type WebhookIssue struct {
+	Repository string `json:"repository,omitempty"`
+	ID         int64  `json:"id,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Problem    string `json:"problem,omitempty"`
+}
+
+// Check returns the security problems of the webhook. If allowedHosts is not empty, the webhook must deliver to one
+// of the hosts or their subdomains.
+func (s *Webhook) Check(allowedHosts []string) []WebhookIssue {
+	var issues []WebhookIssue
+	issue := func(problem string) {
+		issues = append(issues, WebhookIssue{Repository: s.Repository, ID: s.ID, URL: s.URL, Problem: problem})
+	}
+	if !strings.HasPrefix(strings.ToLower(s.URL), "https://") {
+		issue("delivers without TLS")
+	}
+	if s.InsecureSSL {
+		issue("SSL verification disabled")
+	}
+	if len(allowedHosts) > 0 {
+		allowed := false
+		for _, host := range allowedHosts {
+			host = strings.ToLower(strings.TrimPrefix(host, "."))
+			if strings.EqualFold(s.Host, host) || strings.HasSuffix(strings.ToLower(s.Host), "."+host) {
+				allowed = true
+			}
+		}
+		if !allowed {
+			issue("host " + s.Host + " is not allowed")
+		}
+	}
+	return issues
+}
+
+// WebhookAudit checks the webhooks of the organization and its non-archived repositories.
+func (s *GithubClient) WebhookAudit(org string, allowedHosts []string, filters ...RepositoryFilter) ([]WebhookIssue, error) {
+	webhooks, err := s.GetOrganizationWebhooks(org)
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := s.GetOrganizationRepositories(org)
+	if err != nil {
+		return nil, err
+	}
+	for _, repository := range FilterRepositories(repositories, filters...) {
+		if repository.Archived {
+			continue
+		}
+		if err := s.LoadRepositoryWebhooks(repository); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, repository.Webhooks...)
+	}
+	var issues []WebhookIssue
+	for _, webhook := range webhooks {
+		issues = append(issues, webhook.Check(allowedHosts)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Repository < issues[j].Repository
+	})
+	return issues, nil
+}
@@ -0,0 +1,36 @@
+package github_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/engage-wf/plugin-github"
+)
+
+var _ = Describe("Webhook", func() {
+	DescribeTable("reports security problems",
+		func(webhook Webhook, allowedHosts []string, expected []string) {
+			var problems []string
+			for _, issue := range webhook.Check(allowedHosts) {
+				Expect(issue.ID).To(Equal(webhook.ID))
+				Expect(issue.URL).To(Equal(webhook.URL))
+				problems = append(problems, issue.Problem)
+			}
+			Expect(problems).To(Equal(expected))
+		},
+		Entry("none for TLS to any host", Webhook{ID: 1, URL: "https://ci.example.com/hook", Host: "ci.example.com"}, nil, nil),
+		Entry("delivery without TLS", Webhook{ID: 2, URL: "http://ci.example.com/hook", Host: "ci.example.com"}, nil,
+			[]string{"delivers without TLS"}),
+		Entry("disabled SSL verification", Webhook{ID: 3, URL: "HTTPS://ci.example.com/hook", Host: "ci.example.com", InsecureSSL: true}, nil,
+			[]string{"SSL verification disabled"}),
+		Entry("none for an allowed host", Webhook{ID: 4, URL: "https://ci.example.com/hook", Host: "ci.example.com"},
+			[]string{"example.org", "CI.example.com"}, nil),
+		Entry("none for subdomains of an allowed host", Webhook{ID: 5, URL: "https://ci.example.com/hook", Host: "ci.example.com"},
+			[]string{".example.com"}, nil),
+		Entry("a host that only ends like an allowed one", Webhook{ID: 6, URL: "https://badexample.com/hook", Host: "badexample.com"},
+			[]string{"example.com"}, []string{"host badexample.com is not allowed"}),
+		Entry("all problems at once", Webhook{ID: 7, URL: "http://hooks.test/", Host: "hooks.test", InsecureSSL: true},
+			[]string{"example.com"}, []string{"delivers without TLS", "SSL verification disabled", "host hooks.test is not allowed"}),
+	)
+})